@@ -0,0 +1,253 @@
+package bchan
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// AckViolationKind says how the ack rule was broken.
+type AckViolationKind int
+
+const (
+	// MissingAck is a receive that no BcastAck
+	// followed within the grace period.
+	MissingAck AckViolationKind = iota
+
+	// SpuriousAck is a BcastAck with no receive
+	// owing it, such as a second ack for one
+	// receive.
+	SpuriousAck
+)
+
+func (k AckViolationKind) String() string {
+	switch k {
+	case MissingAck:
+		return "missing ack"
+	case SpuriousAck:
+		return "spurious ack"
+	}
+	return fmt.Sprintf("AckViolationKind(%d)", int(k))
+}
+
+// AckViolation reports a breach of the ack rule
+// found by CheckAcks.
+type AckViolation struct {
+	Kind AckViolationKind
+
+	// Receiver names the registered receiver
+	// (see RegisterReceiver) that received, or
+	// acked; it is empty for anonymous ones.
+	Receiver string
+
+	// Site is the file:line of the receive that
+	// went without a BcastAck, for a MissingAck,
+	// or of the BcastAck, for a SpuriousAck.
+	Site string
+
+	// At is when that receive or ack happened.
+	At time.Time
+}
+
+func (v AckViolation) String() string {
+	if v.Receiver != "" {
+		return fmt.Sprintf("bchan: %v by receiver %q at %s (%v)", v.Kind, v.Receiver, v.Site, v.At.Format(time.RFC3339Nano))
+	}
+	return fmt.Sprintf("bchan: %v at %s (%v)", v.Kind, v.Site, v.At.Format(time.RFC3339Nano))
+}
+
+// ackCheck tracks receives and acks for
+// CheckAcks and SetAckDeadline.
+type ackCheck struct {
+	grace  time.Duration
+	report func(AckViolation)
+
+	deadline time.Duration
+	slow     func(SlowAck)
+
+	// pending receives, oldest first;
+	// each BcastAck settles the oldest.
+	pending []*recvSite
+	timer   Timer
+}
+
+type recvSite struct {
+	who      string
+	site     string
+	at       time.Time
+	seq      uint64
+	reported bool
+}
+
+// CheckAcks is a debug mode for finding receivers
+// that forget the ack rule, the commonest way to
+// starve a Bchan without any error. While it is on,
+// receive with RecvChecked, which notes each call
+// site; if BcastAck has not followed a receive
+// within grace, report is called with where it
+// happened, and so it is for any BcastAck that no
+// receive calls for. Each report also counts
+// towards Stats.AckViolations. Go cannot tell
+// which goroutine acks, so acks settle receives
+// oldest first: the counts are exact, and the
+// sites named are right whenever receivers ack
+// promptly, as they should. Recv,
+// TryRecv and ToChan ack for themselves and are
+// fine to mix in; bare receives from Ch are not
+// seen, so their acks show up as spurious.
+// report runs with b locked, so it must be
+// quick and must not call back into b. A grace
+// <= 0 turns checking off.
+func (b *Of[T]) CheckAcks(grace time.Duration, report func(AckViolation)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if grace <= 0 || report == nil {
+		grace, report = 0, nil
+	}
+	c := b.tracking()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.grace, c.report = grace, report
+	b.retrack()
+}
+
+// tracking returns b's ackCheck, making it if need be.
+// Caller must hold b.mu.
+func (b *Of[T]) tracking() *ackCheck {
+	if b.ackCheck == nil {
+		b.ackCheck = &ackCheck{}
+		b.checkingAcks.Store(true)
+	}
+	return b.ackCheck
+}
+
+// retrack drops b's ackCheck once neither
+// CheckAcks nor SetAckDeadline needs it, or
+// else restarts any sweep of overdue receives.
+// Caller must hold b.mu.
+func (b *Of[T]) retrack() {
+	c := b.ackCheck
+	if c.report == nil && c.slow == nil {
+		if c.timer != nil {
+			c.timer.Stop()
+		}
+		b.ackCheck = nil
+		b.checkingAcks.Store(false)
+		return
+	}
+	if c.report != nil && c.timer == nil && len(c.pending) > 0 {
+		c.timer = b.afterFunc(0, func() { b.sweepAcks(c) })
+	}
+}
+
+// RecvChecked is Recv without the BcastAck: it
+// receives from Ch and, under CheckAcks or
+// SetAckDeadline, records the caller as owing an
+// ack. It returns ctx.Err() if ctx is done first,
+// and ErrClosed once b is closed.
+func (b *Of[T]) RecvChecked(ctx context.Context) (val T, err error) {
+	select {
+	case v, ok := <-b.ch():
+		if !ok {
+			return val, ErrClosed
+		}
+		b.received(recvDepth, "")
+		return v, nil
+	case <-ctx.Done():
+		return val, ctx.Err()
+	}
+}
+
+// Frames from callSite up to the user's call, for
+// the skip of received and settleAck.
+const (
+	// callSite, received, Recv (or RecvChecked, ...).
+	recvDepth = 3
+
+	// callSite, settleAck, ackBy, ack (or ackAs),
+	// BcastAck (or Ack).
+	ackDepth = 5
+)
+
+// callSite names the file:line skip frames up,
+// counting callSite itself as 0.
+func callSite(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// received notes a receive owing an ack, made by
+// the receiver named who (if registered) at the
+// call site skip frames up (see callSite).
+func (b *Of[T]) received(skip int, who string) {
+	if !b.checkingAcks.Load() {
+		return
+	}
+	site := callSite(skip)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.ackCheck
+	if c == nil {
+		return
+	}
+	c.pending = append(c.pending, &recvSite{who: who, site: site, at: b.now(), seq: b.seq})
+	if c.report != nil && c.timer == nil {
+		c.timer = b.afterFunc(c.grace, func() { b.sweepAcks(c) })
+	}
+}
+
+// settleAck matches an ack, by the receiver named
+// who if registered, to the oldest receive,
+// reporting it if there is none. skip locates the
+// caller of BcastAck, as for callSite.
+// Caller must hold b.mu.
+func (b *Of[T]) settleAck(skip int, who string) {
+	c := b.ackCheck
+	if c == nil {
+		return
+	}
+	if len(c.pending) == 0 {
+		if c.report != nil {
+			b.nviolations++
+			c.report(AckViolation{Kind: SpuriousAck, Receiver: who, Site: callSite(skip), At: b.now()})
+		}
+		return
+	}
+	p := c.pending[0]
+	c.pending[0] = nil
+	c.pending = c.pending[1:]
+	if held := b.now().Sub(p.at); c.slow != nil && held > c.deadline {
+		b.nslow++
+		c.slow(SlowAck{Receiver: p.who, Site: p.site, Seq: p.seq, Held: held})
+	}
+}
+
+// sweepAcks reports receives left unacked
+// past the grace period.
+func (b *Of[T]) sweepAcks(c *ackCheck) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ackCheck != c || c.report == nil {
+		return // checking was turned off or reset.
+	}
+	c.timer = nil
+	now := b.now()
+	for _, p := range c.pending {
+		if p.reported {
+			continue
+		}
+		if wait := p.at.Add(c.grace).Sub(now); wait > 0 {
+			c.timer = b.afterFunc(wait, func() { b.sweepAcks(c) })
+			return
+		}
+		p.reported = true
+		b.nviolations++
+		c.report(AckViolation{Kind: MissingAck, Receiver: p.who, Site: p.site, At: p.at})
+	}
+}
@@ -0,0 +1,117 @@
+package bchan_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestCheckAcks(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	var mu sync.Mutex
+	var got []bchan.AckViolation
+	bc.CheckAcks(20*time.Millisecond, func(v bchan.AckViolation) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, v)
+	})
+	bc.Bcast(1)
+	ctx := context.Background()
+
+	// a well-behaved receiver.
+	if _, err := bc.RecvChecked(ctx); err != nil {
+		t.Fatal(err)
+	}
+	bc.BcastAck()
+	if _, err := bc.Recv(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// a forgetful one.
+	if _, err := bc.RecvChecked(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0].Kind != bchan.MissingAck {
+		t.Fatalf("want one MissingAck, got %v", got)
+	}
+	if !strings.Contains(got[0].Site, "ackcheck_test.go:") {
+		t.Fatalf("violation names %q, want this test file", got[0].Site)
+	}
+}
+
+func TestCheckAcksOff(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.CheckAcks(time.Millisecond, func(v bchan.AckViolation) {
+		t.Errorf("unexpected report %v", v)
+	})
+	bc.CheckAcks(0, nil)
+	bc.Bcast(1)
+	bc.RecvChecked(context.Background())
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestCheckAcksSpurious(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	var got []bchan.AckViolation
+	bc.CheckAcks(time.Minute, func(v bchan.AckViolation) {
+		got = append(got, v)
+	})
+	bc.Bcast(1)
+	if _, err := bc.RecvChecked(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	bc.BcastAck()
+	bc.BcastAck() // double ack
+
+	if len(got) != 1 || got[0].Kind != bchan.SpuriousAck {
+		t.Fatalf("want one SpuriousAck, got %v", got)
+	}
+	if !strings.Contains(got[0].Site, "ackcheck_test.go:") {
+		t.Fatalf("violation names %q, want this test file", got[0].Site)
+	}
+	if n := bc.Stats().AckViolations; n != 1 {
+		t.Fatalf("Stats.AckViolations = %d, want 1", n)
+	}
+}
+
+func TestCheckAcksWaitAny(t *testing.T) {
+
+	bc := bchan.New(1)
+	typed := bchan.NewOf[int](1)
+	var mu sync.Mutex
+	var got []bchan.AckViolation
+	report := func(v bchan.AckViolation) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, v)
+	}
+	bc.CheckAcks(20*time.Millisecond, report)
+	typed.CheckAcks(20*time.Millisecond, report)
+	bc.Bcast("a")
+	typed.Bcast(1)
+	ctx := context.Background()
+	if _, _, err := bchan.WaitAny(ctx, bc); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := bchan.WaitAnyOf(ctx, typed); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 0 {
+		t.Fatalf("WaitAny's acks reported as %v", got)
+	}
+}
@@ -0,0 +1,97 @@
+package bchan
+
+import (
+	"time"
+)
+
+// AskOf is a broadcast question that carries its
+// own way back: a reply channel the package makes
+// for each Ask and tears down when Ask is done, so
+// receivers can answer with Answer and need no
+// table of pending requests to find the asker.
+type AskOf[Q, R any] struct {
+	Val Q
+	Seq uint64
+
+	replies chan R
+	done    chan struct{}
+}
+
+// Answer sends r back to whoever broadcast a.
+// It blocks only until the asker takes r or
+// gives up; once the Ask has finished, or if a
+// was never sent by Ask, it returns ErrNoRequest.
+func (a AskOf[Q, R]) Answer(r R) error {
+	if a.replies == nil {
+		return ErrNoRequest
+	}
+	select {
+	case <-a.done:
+		return ErrNoRequest
+	default:
+	}
+	select {
+	case a.replies <- r:
+		return nil
+	case <-a.done:
+		return ErrNoRequest
+	}
+}
+
+// Ask broadcasts val on b, wrapped with a fresh
+// reply channel, and gathers the answers
+// receivers send with Answer for timeout. It
+// never debounces. It returns the answers in the
+// order they came; if b moves on to another value
+// first, it stops early with those so far and
+// ErrSuperseded, and if b is closed, with
+// ErrClosed. Over the rate limit, or held back by
+// SetBackpressure, it asks nothing and returns
+// ErrRateLimited or ErrBackpressure. The reply
+// channel is released when Ask returns, so late
+// answers get ErrNoRequest.
+func Ask[Q, R any](b *Of[AskOf[Q, R]], val Q, timeout time.Duration) ([]R, error) {
+	w, stop := b.watch()
+	defer stop()
+
+	b.lockForBcast()
+	if !b.isOpenFor("Ask") {
+		b.mu.Unlock()
+		return nil, ErrClosed
+	}
+	a := AskOf[Q, R]{
+		Val:     val,
+		replies: make(chan R, cap(b.Ch)),
+		done:    make(chan struct{}),
+	}
+	if err := b.admitNow("Ask", &a, true); err != nil {
+		b.mu.Unlock()
+		return nil, err
+	}
+	a.Seq = b.seq + 1
+	b.cancelPending()
+	b.store("Ask", a)
+	seq := b.seq
+	b.activate()
+	b.mu.Unlock()
+	defer close(a.done)
+
+	due, timer := b.timeout(timeout)
+	defer timer.Stop()
+	var got []R
+	for {
+		select {
+		case r := <-a.replies:
+			got = append(got, r)
+		case _, ok := <-w:
+			if !ok {
+				return got, ErrClosed
+			}
+			if b.Seq() != seq {
+				return got, ErrSuperseded
+			}
+		case <-due:
+			return got, nil
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package bchan_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestAsk(t *testing.T) {
+
+	bc := bchan.NewOf[bchan.AskOf[string, int]](3)
+	for i := 0; i < 3; i++ {
+		go func(i int) {
+			a := <-bc.Ch
+			bc.BcastAck()
+			if a.Val == "roll call" {
+				a.Answer(i)
+			}
+		}(i)
+	}
+
+	got, err := bchan.Ask(bc, "roll call", 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Ints(got)
+	if len(got) != 3 || got[0] != 0 || got[1] != 1 || got[2] != 2 {
+		t.Fatalf("got answers %v, want [0 1 2]", got)
+	}
+
+	a := bc.Get()
+	if a.Seq != 1 {
+		t.Fatalf("ask has Seq %d, want 1", a.Seq)
+	}
+	if err := a.Answer(9); err != bchan.ErrNoRequest {
+		t.Fatalf("a late answer got %v, want ErrNoRequest", err)
+	}
+	var zero bchan.AskOf[string, int]
+	if err := zero.Answer(9); err != bchan.ErrNoRequest {
+		t.Fatalf("answering a bare AskOf got %v, want ErrNoRequest", err)
+	}
+}
+
+func TestAskClosed(t *testing.T) {
+
+	bc := bchan.NewOf[bchan.AskOf[string, int]](1)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bc.Close()
+	}()
+	if _, err := bchan.Ask(bc, "anyone?", 5*time.Second); err != bchan.ErrClosed {
+		t.Fatalf("got %v, want ErrClosed", err)
+	}
+}
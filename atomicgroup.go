@@ -0,0 +1,178 @@
+package bchan
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Member is any Bchan, whatever its value type,
+// as far as an AtomicGroup is concerned. Only *Of[T]
+// implements it.
+type Member interface {
+	lockID() uint64
+	lock()
+	unlock()
+	checkAny(val interface{}) (interface{}, error)
+	admitAny(val interface{}) (interface{}, error)
+	bcastAny(op string, val interface{})
+	getAny() interface{}
+}
+
+// AtomicGroup broadcasts on several related Bchans
+// as one step. While an AtomicGroup.Bcast is in
+// progress it holds every member's lock, taken in a
+// fixed global order so that overlapping groups
+// cannot deadlock; thus no Get, Cur,
+// AtomicGroup.Snapshot, or other Bchan call can
+// observe some members updated and others not, and
+// two producers updating the same set cannot
+// interleave into a torn combination of values.
+type AtomicGroup struct {
+	members []Member // in the order given
+	ordered []Member // in lock order
+}
+
+// NewAtomicGroup makes an AtomicGroup over members,
+// which must be distinct.
+func NewAtomicGroup(members ...Member) *AtomicGroup {
+	ordered := append([]Member(nil), members...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].lockID() < ordered[j].lockID()
+	})
+	for i := 1; i < len(ordered); i++ {
+		if ordered[i].lockID() == ordered[i-1].lockID() {
+			panic("bchan: NewAtomicGroup given the same Bchan twice")
+		}
+	}
+	return &AtomicGroup{members: members, ordered: ordered}
+}
+
+// Bcast broadcasts vals[i] on the i-th member, all
+// at once. Each value must suit its member's type
+// and pass its interceptors and validator (see
+// SetValidator). If any does not, or any member is
+// closed, Bcast changes nothing and returns the
+// error. Every value is checked against its
+// member's type and, for a member without
+// interceptors, its validator before any
+// interceptor runs, so a bad value never reaches
+// another member's interceptors; a rejection by an
+// interceptor itself comes after earlier members'
+// interceptors have seen their values. These
+// broadcasts are immediate, never debounced, and
+// refused with ErrRateLimited or ErrBackpressure if
+// any member's SetRateLimit or SetBackpressure
+// would hold its value back.
+func (g *AtomicGroup) Bcast(vals ...interface{}) error {
+	if len(vals) != len(g.members) {
+		return fmt.Errorf("bchan: AtomicGroup.Bcast given %d values for %d members", len(vals), len(g.members))
+	}
+	g.lock()
+	defer g.unlock()
+	for i, m := range g.members {
+		if _, err := m.checkAny(vals[i]); err != nil {
+			return fmt.Errorf("bchan: AtomicGroup.Bcast value %d: %w", i, err)
+		}
+	}
+	admitted := make([]interface{}, len(vals))
+	for i, m := range g.members {
+		v, err := m.admitAny(vals[i])
+		if err != nil {
+			return fmt.Errorf("bchan: AtomicGroup.Bcast value %d: %w", i, err)
+		}
+		admitted[i] = v
+	}
+	for i, m := range g.members {
+		m.bcastAny("AtomicGroup.Bcast", admitted[i])
+	}
+	return nil
+}
+
+// Snapshot returns the current value of every
+// member, in member order, as one consistent read.
+func (g *AtomicGroup) Snapshot() []interface{} {
+	g.lock()
+	defer g.unlock()
+	out := make([]interface{}, len(g.members))
+	for i, m := range g.members {
+		out[i] = m.getAny()
+	}
+	return out
+}
+
+func (g *AtomicGroup) lock() {
+	for _, m := range g.ordered {
+		m.lock()
+	}
+}
+
+func (g *AtomicGroup) unlock() {
+	for i := len(g.ordered) - 1; i >= 0; i-- {
+		g.ordered[i].unlock()
+	}
+}
+
+func (b *Of[T]) lockID() uint64 { return b.id }
+func (b *Of[T]) lock()          { b.mu.Lock() }
+func (b *Of[T]) unlock()        { b.mu.Unlock() }
+
+// checkAny returns val as a T, if b is open, ready
+// for it, and val suits it, without spending a rate
+// token or running anything of the user's but the
+// validator, and that only when b has no
+// interceptors to change val first.
+// Caller must hold b.mu.
+func (b *Of[T]) checkAny(val interface{}) (interface{}, error) {
+	if b.closed {
+		return nil, ErrClosed
+	}
+	if b.backpressured() {
+		b.ndropped.Add(1)
+		return nil, ErrBackpressure
+	}
+	if b.limit != nil && b.limit.wait(b.now()) > 0 {
+		b.ndropped.Add(1)
+		return nil, ErrRateLimited
+	}
+	var v T
+	if val != nil {
+		var ok bool
+		if v, ok = val.(T); !ok {
+			return nil, fmt.Errorf("a %T will not go on a Bchan of %T", val, v)
+		}
+	}
+	if len(b.bcastICs) == 0 && b.validator != nil {
+		if err := b.validator(b.reduce(v)); err != nil {
+			b.ndropped.Add(1)
+			return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+		}
+	}
+	return v, nil
+}
+
+// admitAny returns val, which has passed checkAny,
+// as it will be stored, once through b's
+// interceptors. Caller must hold b.mu.
+func (b *Of[T]) admitAny(val interface{}) (interface{}, error) {
+	v, _ := val.(T)
+	if err := b.admit("AtomicGroup.Bcast", &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// caller must hold b.mu, and have passed admitAny.
+func (b *Of[T]) bcastAny(op string, val interface{}) {
+	v, _ := val.(T)
+	if b.limit != nil {
+		b.limit.take(b.now())
+	}
+	b.cancelPending()
+	b.store(op, v)
+	b.activate()
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) getAny() interface{} {
+	return b.cur
+}
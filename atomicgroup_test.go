@@ -0,0 +1,90 @@
+package bchan_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestAtomicGroupBcast(t *testing.T) {
+
+	cfg := bchan.NewOf[int](1)
+	flags := bchan.NewOf[string](1)
+	g := bchan.NewAtomicGroup(cfg, flags)
+	g.Bcast(0, "v0")
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 500; i++ {
+			g.Bcast(i, "v"+strconv.Itoa(i))
+		}
+		close(stop)
+	}()
+
+	// a reader never sees a torn pair.
+	for done := false; !done; {
+		select {
+		case <-stop:
+			done = true
+		default:
+		}
+		snap := g.Snapshot()
+		if "v"+strconv.Itoa(snap[0].(int)) != snap[1].(string) {
+			t.Fatalf("torn snapshot %v", snap)
+		}
+	}
+	wg.Wait()
+
+	// overlapping groups in the opposite order do not deadlock.
+	g2 := bchan.NewAtomicGroup(flags, cfg)
+	var wg2 sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg2.Add(2)
+		go func() { defer wg2.Done(); g.Bcast(1, "v1") }()
+		go func() { defer wg2.Done(); g2.Bcast("v2", 2) }()
+	}
+	wg2.Wait()
+
+	if err := g.Bcast(-1, 42); err == nil {
+		t.Fatal("mistyped value should be an error")
+	}
+	if cfg.Get() == -1 {
+		t.Fatal("a failed AtomicGroup.Bcast must change nothing")
+	}
+}
+
+func TestAtomicGroupInvalid(t *testing.T) {
+
+	cfg := bchan.NewOf[int](1)
+	flags := bchan.NewOf[string](1)
+	var seen []int
+	cfg.InterceptBcast(func(op string, v int, next func(v int) error) error {
+		seen = append(seen, v)
+		return next(v)
+	})
+	flags.SetValidator(func(v string) error {
+		if v == "" {
+			return errors.New("empty")
+		}
+		return nil
+	})
+	g := bchan.NewAtomicGroup(cfg, flags)
+	if err := g.Bcast(1, ""); !errors.Is(err, bchan.ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+	if len(seen) != 0 || cfg.IsOn() || flags.IsOn() {
+		t.Fatalf("rejected Bcast had effects: seen %v, on %v %v", seen, cfg.IsOn(), flags.IsOn())
+	}
+	if err := g.Bcast(1, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1 || cfg.Get() != 1 || flags.Get() != "a" {
+		t.Fatalf("seen %v, got %v %q", seen, cfg.Get(), flags.Get())
+	}
+}
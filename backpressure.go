@@ -0,0 +1,90 @@
+package bchan
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrBackpressure is returned by TryBcast,
+// BcastAndWait, BcastSync and the other immediate
+// broadcasts, and makes Bcast drop its value, when
+// the value before it has not been acked enough;
+// see SetBackpressure.
+var ErrBackpressure = errors.New("bchan: previous value not yet acked enough")
+
+// SetBackpressure keeps unacknowledged state
+// transitions from piling up: a Bcast made while
+// the current value has been acked by fewer than
+// minAcks receivers waits, up to wait, for the
+// rest, and if they do not come it drops the new
+// value (logging it, as for a rate limit) rather
+// than replace an unseen one. With wait <= 0 it
+// drops at once; TryBcast never waits and reports
+// the drop as ErrBackpressure. No acks are needed
+// while b is off. minAcks <= 0 turns backpressure
+// off. Bcast waits before it takes up the new
+// value at all, without b's lock, so other calls,
+// and the acks, go on meanwhile. Values broadcast
+// by package goroutines (Link, Merge, FromChan and
+// the like) wait the same way.
+func (b *Of[T]) SetBackpressure(minAcks int, wait time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if minAcks < 0 {
+		minAcks = 0
+	}
+	b.backMin = minAcks
+	b.backWait = wait
+}
+
+// backpressured reports whether a new value must
+// be held back for want of acks of the current one.
+// Caller must hold b.mu.
+func (b *Of[T]) backpressured() bool {
+	return b.backMin > 0 && b.seq > 0 && b.on && b.seqAcks.Load() < uint64(b.backMin)
+}
+
+// heldBack reports whether bcast would refuse a
+// value for backpressure: debounced values are
+// only coalesced, and so are not held back.
+// Caller must hold b.mu.
+func (b *Of[T]) heldBack() bool {
+	return b.debounce <= 0 && !b.hasPending && b.backpressured()
+}
+
+// lockForBcast takes b.mu for a broadcast, first
+// waiting, up to the SetBackpressure wait, for the
+// current value to be acked enough. It gives up the
+// lock while waiting, and so is done before anything
+// about the new value is worked out or staged; bcast
+// then checks again, without waiting.
+func (b *Of[T]) lockForBcast() {
+	b.mu.Lock()
+	if b.backWait <= 0 || !b.heldBack() {
+		return
+	}
+	deadline := b.now().Add(b.backWait)
+	for !b.closed {
+		// arm the wakeup before counting; see ack.
+		if b.ackWait == nil {
+			b.ackWait = make(chan struct{})
+			b.ackWaiting.Store(true)
+		}
+		wake := b.ackWait
+		if !b.heldBack() {
+			return
+		}
+		left := deadline.Sub(b.now())
+		if left <= 0 {
+			return
+		}
+		due, timer := b.timeout(left)
+		b.mu.Unlock()
+		select {
+		case <-wake:
+		case <-due:
+		}
+		timer.Stop()
+		b.mu.Lock()
+	}
+}
@@ -0,0 +1,81 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestBackpressure(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	bc.SetBackpressure(2, 0)
+	bc.Bcast(1)
+
+	if err := bc.TryBcast(2); err != bchan.ErrBackpressure {
+		t.Fatalf("no acks yet; expected ErrBackpressure, got %v", err)
+	}
+	bc.Bcast(2)
+	if v := bc.Get(); v != 1 {
+		t.Fatalf("Bcast replaced an unacked value; have %d", v)
+	}
+
+	for i := 0; i < 2; i++ {
+		<-bc.Ch
+		bc.BcastAck()
+	}
+	if err := bc.TryBcast(3); err != nil {
+		t.Fatalf("acked twice; TryBcast got %v", err)
+	}
+
+	// with a wait, Bcast blocks for the acks.
+	bc.SetBackpressure(2, 5*time.Second)
+	go func() {
+		for i := 0; i < 2; i++ {
+			time.Sleep(5 * time.Millisecond)
+			<-bc.Ch
+			bc.BcastAck()
+		}
+	}()
+	bc.Bcast(4)
+	if v := bc.Get(); v != 4 {
+		t.Fatalf("Bcast should have waited for the acks; have %d", v)
+	}
+
+	bc.SetBackpressure(0, 0)
+	bc.Bcast(5)
+	if v := bc.Get(); v != 5 {
+		t.Fatalf("backpressure off; have %d", v)
+	}
+}
+
+func TestBackpressureWaitsBeforeAdmitting(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[int](bchan.WithReducer(func(cur, in int) int { return cur + in }))
+	bc.SetBackpressure(1, 5*time.Second)
+	bc.Bcast(1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bc.BcastMeta(10, bchan.Meta{Source: "a"})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// while BcastMeta waits, b moves on; its value
+	// must merge into what b holds when it goes out.
+	bc.Set(100)
+	bc.On()
+	<-bc.Ch
+	bc.BcastAck()
+	<-done
+
+	e := bc.Envelope()
+	if e.Val != 111 {
+		t.Fatalf("expected 1+100+10 = 111, have %d", e.Val)
+	}
+	if e.Meta.Source != "a" {
+		t.Fatalf("BcastMeta's Meta was lost: %+v", e.Meta)
+	}
+}
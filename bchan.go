@@ -8,18 +8,248 @@
 package bchan
 
 import (
+	"context"
+	"errors"
+	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// ErrClosed is returned by receive helpers
+// once the Bchan has been Close()-d.
+var ErrClosed = errors.New("bchan: closed")
+
+// nextID hands out Of.id values.
+var nextID atomic.Uint64
+
 // Bchan is an 1:M non-blocking value-loadable channel.
 // The client needs to only know about one
 // rule: after a receive on Ch, you must call Bchan.BcastAck().
 //
-type Bchan struct {
-	Ch  chan interface{}
-	mu  sync.Mutex
+// Bchan carries interface{} values; see Of for a
+// variant whose Ch carries a specific type T.
+type Bchan = Of[interface{}]
+
+// Of is the type-parameterized form of Bchan.
+// Ch is a chan T, and Set/Bcast take a T, so
+// receivers need no type assertions and a single
+// channel cannot accidentally carry mixed types.
+// The same BcastAck() rule applies.
+type Of[T any] struct {
+	// Ch is the broadcast channel. Code that
+	// should not be able to send on, close, or
+	// swap it can receive from RecvCh instead, or
+	// use Recv and TryRecv; code on a Bchan that
+	// may be resized or shrunk must, as reading
+	// Ch directly then races with the swap.
+	Ch chan T
+	id uint64 // unique; fixes lock order across Bchans
+
+	// mu guards what follows. Calls that only
+	// read state, such as Get, Seq and IsOn,
+	// share it, so they never queue behind each
+	// other, only behind changes.
+	mu  sync.RWMutex
 	on  bool
-	cur interface{}
+	cur T
+
+	closed bool
+
+	// live is whether subscriptions are being
+	// fed; Set holds them back until On.
+	live bool
+	subs map[*SubscriptionOf[T]]struct{}
+
+	groups map[string]*ConsumerGroupOf[T]
+
+	hist *ring[T]
+
+	// seq counts calls to Set and Bcast.
+	seq uint64
+
+	// for Stats
+	nfill  atomic.Uint64
+	ndrain atomic.Uint64
+	nack   atomic.Uint64
+	setAt  time.Time
+
+	ackObs    func(d time.Duration)
+	hasAckObs atomic.Bool
+
+	logger Logger
+
+	// clock, if set, stands in for the
+	// system clock; see WithClock.
+	clock Clock
+
+	// profCtx carries the labels set by
+	// WithProfileLabels.
+	profCtx context.Context
+
+	// hook and redact are set by SetEventHook.
+	hook   func(e Event)
+	redact func(v T) interface{}
+
+	// seqAcks counts acks since the last Set or
+	// Bcast; ackWait, if not nil, is closed on
+	// the next ack to wake BcastAndWait, and
+	// ackWaiting says so to lock-free acks.
+	// seqAckers names the registered receivers
+	// among them, for BcastAndWait.
+	// seqFills counts copies put into Ch since,
+	// less those drained, for BcastSync, and
+	// chFills all along, for WaitEmpty.
+	seqAcks    atomic.Uint64
+	seqAckers  map[string]struct{}
+	seqFills   uint64
+	chFills    uint64
+	ackWait    chan struct{}
+	ackWaiting atomic.Bool
+
+	// ackCh is Ch, for acks to peek at
+	// without the lock; see setCh.
+	ackCh atomic.Pointer[chan T]
+
+	// quorum, if not nil, tracks receipt of
+	// the current value by subscriptions.
+	quorum *Quorum
+
+	// debounce, if > 0, is the window within
+	// which Bcast calls are coalesced.
+	debounce   time.Duration
+	pending    T
+	hasPending bool
+	flushTimer Timer
+	flushGen   uint64
+
+	limit *bucket
+
+	equal func(a, b T) bool
+
+	// stopped is closed when broadcasting next
+	// turns off; see Stopped.
+	stopped chan struct{}
+
+	// retired are the Chs replaced by Resize and
+	// still served (see replaceCh); hasRetired
+	// says so to lock-free acks. moved is closed
+	// at the next replacement; see Moved.
+	retired    []*retiredCh[T]
+	hasRetired atomic.Bool
+	moved      chan struct{}
+
+	paused bool
+
+	// stamp, if set, fills in an enveloped
+	// value's bookkeeping as it is stored,
+	// by clock.
+	stamp func(val T, seq uint64, at time.Time, m Meta, clock Clock) T
+
+	// meta describes the current value. staged
+	// is set by BcastMeta just before storing.
+	meta        Meta
+	staged      Meta
+	pendingMeta Meta
+
+	// watchers are signalled on every publish.
+	watchers map[chan struct{}]bool
+
+	// quiet makes use after Close a no-op
+	// rather than a panic.
+	quiet bool
+
+	// idle watchdog; see SetIdleTimeout.
+	idle      time.Duration
+	idleTimer Timer
+	idleGen   uint64
+
+	ttlTimer Timer
+
+	// heartbeat; see SetHeartbeat.
+	beat      time.Duration
+	beatTimer Timer
+	beatGen   uint64
+	freshAt   time.Time
+	restamp   func(val T, fresh time.Time) T
+
+	// journal, if not nil, records transitions;
+	// see Journal.
+	journal     Encoder
+	journalFile *os.File
+	journaled   bool
+	journalSeq  uint64
+	journalOn   bool
+
+	codec Codec
+
+	// clone, if set, copies values handed out;
+	// see SetClone.
+	clone func(v T) T
+
+	validator func(v T) error
+
+	// interceptor chains; out composes clone and
+	// deliverICs, and is nil if both are unset.
+	bcastICs   []BcastInterceptor[T]
+	deliverICs []DeliverInterceptor[T]
+	out        func(v T) T
+
+	// observers are notified by one dispatcher
+	// goroutine, once dispatching; see AddObserver.
+	observers   []*observer[T]
+	dispatching bool
+
+	reducer func(cur, incoming T) T
+
+	// req is the open BcastRequest, if any.
+	req *request
+
+	// futures are waiting, from Next.
+	futures map[*FutureOf[T]]struct{}
+
+	// cond wakes RecvAfter; condBackend means
+	// Ch is never filled (see WithCondBackend).
+	cond        *sync.Cond
+	condBackend bool
+
+	// stepFills leaves filling Ch to StepFill,
+	// which owes owed copies; see WithStepFills.
+	stepFills bool
+	owed      int
+
+	// checkedSeq is the seq at the last
+	// CheckInvariants; invReport is set by
+	// WithInvariantCheck.
+	checkedSeq uint64
+	invReport  func(err error)
+
+	// ackCheck is set by CheckAcks, and
+	// checkingAcks says so to lock-free acks.
+	ackCheck     *ackCheck
+	checkingAcks atomic.Bool
+	nviolations  uint64
+	nslow        uint64
+
+	receivers map[string]*ReceiverOf[T]
+
+	// exactLog lists the seqs broadcast while
+	// ExactlyOnce receivers were registered, as
+	// the account Deliveries checks them against.
+	exactLog []uint64
+
+	// onceFilled, in at-most-once mode, holds the
+	// seq each channel was last filled for.
+	onceFilled map[chan T]uint64
+
+	// backMin and backWait are set by
+	// SetBackpressure.
+	backMin  int
+	backWait time.Duration
+
+	// event counters for Counters.
+	nbcast   atomic.Uint64
+	ndropped atomic.Uint64
 }
 
 // New constructor should be told
@@ -35,22 +265,75 @@ type Bchan struct {
 // linearly to the service time
 // as they are maintained.
 func New(expectedDiameter int) *Bchan {
+	return NewOf[interface{}](expectedDiameter)
+}
+
+// NewOf makes a new Of[T]. See New for
+// the meaning of expectedDiameter.
+func NewOf[T any](expectedDiameter int) *Of[T] {
 	if expectedDiameter <= 0 {
 		expectedDiameter = 1
 	}
-	return &Bchan{
-		Ch: make(chan interface{}, expectedDiameter+1),
-	}
+	b := &Of[T]{id: nextID.Add(1)}
+	b.setCh(make(chan T, expectedDiameter+1))
+	return b
+}
+
+// setCh makes ch b's Ch.
+// Caller must hold b.mu, or own b outright.
+func (b *Of[T]) setCh(ch chan T) {
+	b.Ch = ch
+	b.ackCh.Store(&ch)
 }
 
 // On turns on the broadcast channel without
 // changing the value to be transmitted.
 //
-func (b *Bchan) On() {
+func (b *Of[T]) On() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	if !b.isOpenFor("On") {
+		return
+	}
+	b.turnOn()
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) turnOn() {
+	if b.logger != nil {
+		// guarded: boxing the args would allocate.
+		b.logf("bchan: On seq=%d", b.seq)
+	}
 	b.on = true
 	b.fill()
+	b.publish(true)
+	b.emit("On")
+}
+
+// Off turns off the broadcast channel without
+// changing the value to be transmitted; On
+// resumes it. Receivers waiting on Stopped()
+// are notified.
+func (b *Of[T]) Off() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.off()
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) off() {
+	if b.logger != nil {
+		// guarded: boxing the args would allocate.
+		b.logf("bchan: Off seq=%d", b.seq)
+	}
+	b.cancelPending()
+	b.turnOff()
+	b.drain()
+	b.publish(false)
+	b.emit("Off")
 }
 
 // Set stores a value to be broadcast
@@ -60,51 +343,261 @@ func (b *Bchan) On() {
 // See also Bcast() that does Set()
 // followed by On() in one call.
 //
-func (b *Bchan) Set(val interface{}) {
+func (b *Of[T]) Set(val T) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.cur = val
+	if !b.isOpenFor("Set") {
+		return
+	}
+	if b.admit("Set", &val) != nil {
+		return
+	}
+	b.cancelPending()
+	b.store("Set", val)
 	b.drain()
+	b.publish(false)
+}
+
+// store makes val the current value, giving
+// it the next sequence number.
+// Caller must hold b.mu.
+func (b *Of[T]) store(op string, val T) {
+	b.stopTTL()
+	b.endRequest()
+	b.wakeAckWaiter()
+	if b.retired != nil {
+		b.passOverRetired()
+	}
+	b.seq++
+	b.nbcast.Add(1)
+	b.seqAcks.Store(0)
+	clear(b.seqAckers)
+	b.seqFills = 0
+	b.quorum = nil
+	b.setAt = b.now()
+	b.freshAt = b.setAt
+	if b.stamp != nil {
+		val = b.stamp(val, b.seq, b.setAt, b.staged, b.clock)
+	}
+	val = b.copy(val)
+	b.cur = val
+	b.meta = b.staged
+	b.staged = Meta{}
+	b.remember(val)
+	b.kickIdle()
+	if b.logger != nil {
+		b.logf("bchan: %s seq=%d", op, b.seq)
+	}
+	b.emit(op)
 }
 
 // Get returns the currently set
 // broadcast value.
-func (b *Bchan) Get() interface{} {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+func (b *Of[T]) Get() T {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 	return b.cur
 }
 
+// Cur returns the currently set broadcast
+// value along with whether broadcasting is on,
+// as a point-in-time read that does not
+// involve receiving from Ch or acking.
+func (b *Of[T]) Cur() (val T, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.cur, b.on
+}
+
 // Bcast is the common case of doing
 // both Set() and then On() together
 // to start broadcasting a new value.
 //
-func (b *Bchan) Bcast(val interface{}) {
-	b.mu.Lock()
+func (b *Of[T]) Bcast(val T) {
+	b.lockForBcast()
 	defer b.mu.Unlock()
-	b.cur = val
+	if !b.isOpenFor("Bcast") {
+		return
+	}
+	b.bcast("Bcast", val)
+}
+
+// bcast is Bcast with b.mu held, subject
+// to backpressure (take the lock with
+// lockForBcast to wait it out), interceptors,
+// validation, debouncing and rate limit. It
+// returns why val was refused, if it was.
+func (b *Of[T]) bcast(op string, val T) error {
+	if b.heldBack() {
+		b.ndropped.Add(1)
+		b.logf("bchan: %s held back by unacked seq=%d, dropped", op, b.seq)
+		return ErrBackpressure
+	}
+	if err := b.admit(op, &val); err != nil {
+		return err
+	}
+	if b.debounce > 0 || b.hasPending {
+		b.coalesce(val, b.debounce)
+		return nil
+	}
+	if b.limit != nil && !b.limit.take(b.now()) {
+		if b.limit.reject {
+			b.ndropped.Add(1)
+			b.logf("bchan: %s rate limited, dropped", op)
+			return nil
+		}
+		b.coalesce(val, b.limit.wait(b.now()))
+		return nil
+	}
+	b.store(op, val)
+	b.activate()
+	return nil
+}
+
+// activate turns broadcasting on with
+// fresh copies of the current value.
+// Caller must hold b.mu.
+func (b *Of[T]) activate() {
 	b.drain()
 	b.on = true
 	b.fill()
+	b.publish(true)
 }
 
 // Clear turns off broadcasting and
 // empties the channel of any old values.
-func (b *Bchan) Clear() {
+func (b *Of[T]) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.logf("bchan: Clear seq=%d", b.seq)
+	b.clear()
+	b.emit("Clear")
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) clear() {
+	b.cancelPending()
+	b.stopTTL()
+	b.turnOff()
+	b.drain()
+	var zero T
+	b.cur = zero
+	b.publish(false)
+}
+
+// Close shuts down the Bchan for good. It
+// drains and then closes Ch, so receivers see
+// a closed channel rather than blocking forever,
+// and it ends all subscriptions. Any later
+// call to On, Set, or Bcast will panic.
+// Close is safe to call more than once.
+func (b *Of[T]) Close() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.on = false
+	if b.closed {
+		return
+	}
+	b.logf("bchan: Close seq=%d", b.seq)
+	b.shutdown()
+	close(b.Ch)
+	b.emit("Close")
+}
+
+// shutdown does all of Close but closing Ch.
+// Caller must hold b.mu.
+func (b *Of[T]) shutdown() {
+	b.closed = true
+	b.endRequest()
+	b.cancelPending()
+	b.stopTTL()
+	b.kickIdle()
+	if b.beatTimer != nil {
+		b.beatTimer.Stop()
+	}
+	if b.ackCheck != nil && b.ackCheck.timer != nil {
+		b.ackCheck.timer.Stop()
+	}
+	b.turnOff()
 	b.drain()
-	b.cur = nil
+	b.record()
+	b.closeJournal()
+	b.failFutures(ErrClosed)
+	b.wakeExact()
+	b.wakeAckWaiter()
+	if b.cond != nil {
+		b.cond.Broadcast()
+	}
+	for _, g := range b.groups {
+		close(g.Ch)
+	}
+	b.closeRetired()
+	for s := range b.subs {
+		delete(b.subs, s)
+		close(s.done)
+	}
+	for w := range b.watchers {
+		delete(b.watchers, w)
+		close(w)
+	}
+}
+
+// IsClosed reports whether Close has been called.
+func (b *Of[T]) IsClosed() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.closed
+}
+
+// isOpenFor reports whether op may proceed. On a
+// closed Bchan it panics, unless b was made to
+// close quietly (see NewWithContext), in which
+// case it returns false and op should do nothing.
+// Caller must hold b.mu.
+func (b *Of[T]) isOpenFor(op string) bool {
+	if !b.closed {
+		return true
+	}
+	if b.quiet {
+		return false
+	}
+	panic("bchan: " + op + " called on closed Bchan")
 }
 
 // drain all messages, leaving b.Ch empty.
 // Users typically want Clear() instead.
-func (b *Bchan) drain() {
+func (b *Of[T]) drain() {
+	b.wakeAckWaiter()
+	b.owed = 0
+	n := drainCh(b.Ch)
+	b.ndrain.Add(n)
+	b.unfill(n)
+	for _, g := range b.groups {
+		b.ndrain.Add(drainCh(g.Ch))
+	}
+	for _, r := range b.retired {
+		b.drainRetired(r)
+	}
+}
+
+// unfill takes n drained copies off seqFills. Those
+// beyond it were copies of the value before, drained
+// just after the current one was stored.
+// Caller must hold b.mu.
+func (b *Of[T]) unfill(n uint64) {
+	b.seqFills -= min(n, b.seqFills)
+}
+
+// drainCh empties ch, returning how many
+// values it removed.
+func drainCh[T any](ch chan T) (n uint64) {
 	// empty chan
 	for {
 		select {
-		case <-b.Ch:
+		case <-ch:
+			n++
 		default:
 			return
 		}
@@ -117,21 +610,133 @@ func (b *Bchan) drain() {
 // on the channel Ch. This makes such channels
 // self-servicing, as BcastAck will re-fill the
 // async channel with the current value.
-func (b *Bchan) BcastAck() {
+func (b *Of[T]) BcastAck() {
+	b.ack(nil)
+}
+
+// ack does the work of BcastAck for Ch, or
+// for a consumer group's channel if ch is not nil.
+func (b *Of[T]) ack(ch chan T) {
+	b.ackBy(ch, "")
+}
+
+// ackBy is ack by the registered receiver
+// named who, or an anonymous one if who is "".
+func (b *Of[T]) ackBy(ch chan T, who string) {
+	var t0 time.Time
+	if b.observingAcks() {
+		t0 = time.Now()
+	} else if who == "" && !b.checkingAcks.Load() && !b.hasRetired.Load() && b.ackFull(ch) {
+		// nothing to refill, nobody timing us, and
+		// no name to note, so count without the lock. The counts
+		// must be bumped before ackWaiting is read,
+		// as BcastAndWait sets it before reading
+		// them, or a wakeup could be lost.
+		b.nack.Add(1)
+		b.seqAcks.Add(1)
+		if b.ackWaiting.Load() {
+			b.mu.Lock()
+			b.wakeAckWaiter()
+			b.mu.Unlock()
+		}
+		return
+	}
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.nack.Add(1)
+	b.seqAcks.Add(1)
+	if who != "" {
+		if b.seqAckers == nil {
+			b.seqAckers = make(map[string]struct{})
+		}
+		b.seqAckers[who] = struct{}{}
+	}
+	b.wakeAckWaiter()
+	if ch == nil {
+		b.settleAck(ackDepth, who)
+	}
+	if b.ackObs != nil && !t0.IsZero() {
+		obs := b.ackObs
+		defer func() { obs(time.Since(t0)) }()
+	}
+	if ch == nil {
+		ch = b.Ch
+	}
 	if b.on {
-		b.fill()
+		b.fillCh(ch)
+		b.topUpRetired()
 	}
+	b.verify()
 }
 
-// fill up the channel
-func (b *Bchan) fill() {
-	for {
-		select {
-		case b.Ch <- b.cur:
-		default:
-			return
+// ackFull reports, without the lock, whether the
+// channel being acked (Ch if ch is nil) is already
+// full, so that refilling it would do nothing.
+func (b *Of[T]) ackFull(ch chan T) bool {
+	if ch == nil {
+		ch = *b.ackCh.Load()
+	}
+	return len(ch) == cap(ch)
+}
+
+// wakeAckWaiter wakes anyone waiting on acks
+// (BcastAndWait, BcastSync, WaitEmpty and so on).
+// Caller must hold b.mu.
+func (b *Of[T]) wakeAckWaiter() {
+	if b.ackWait != nil {
+		close(b.ackWait)
+		b.ackWait = nil
+		b.ackWaiting.Store(false)
+	}
+}
+
+// fill up the channel, and those of
+// any consumer groups.
+func (b *Of[T]) fill() {
+	b.fillCh(b.Ch)
+	for _, g := range b.groups {
+		b.fillCh(g.Ch)
+	}
+	b.topUpRetired()
+}
+
+func (b *Of[T]) fillCh(ch chan T) {
+	if b.paused || b.condBackend && ch == b.Ch {
+		return
+	}
+	if b.stepFills && ch == b.Ch {
+		b.owed = cap(ch) - len(ch)
+		return
+	}
+	if b.onceFilled != nil {
+		b.fillOnce(ch)
+		return
+	}
+	for b.fillOne(ch) {
+	}
+}
+
+// fillOne puts one copy of the current value
+// into ch, reporting false if ch was full.
+// Caller must hold b.mu.
+func (b *Of[T]) fillOne(ch chan T) bool {
+	v := b.cur
+	if b.out != nil {
+		// only copy for a send that will land.
+		if len(ch) == cap(ch) {
+			return false
+		}
+		v = b.out(v)
+	}
+	select {
+	case ch <- v:
+		b.nfill.Add(1)
+		if ch == b.Ch {
+			b.seqFills++
+			b.chFills++
 		}
+		return true
+	default:
+		return false
 	}
 }
@@ -1,6 +1,7 @@
 package bchan_test
 
 import (
+	"context"
 	"github.com/glycerine/bchan"
 	"testing"
 )
@@ -62,3 +63,93 @@ func TestBchan(t *testing.T) {
 	}
 
 }
+
+func TestOf(t *testing.T) {
+
+	sz := 3
+	bc := bchan.NewOf[string](sz)
+
+	select {
+	case <-bc.Ch:
+		t.Fatal("bc starts open; it should have blocked")
+	default:
+		// ok, good.
+	}
+
+	bc.Bcast("bill")
+	for i := 0; i < sz; i++ {
+		select {
+		case b := <-bc.Ch:
+			bc.BcastAck()
+			if b != "bill" { // no type assertion needed
+				t.Fatal("Bcast(\"bill\") means bill should always be read on the bc")
+			}
+		default:
+			t.Fatal("bc is now closed, should have read back bill.")
+		}
+	}
+
+	bc.Clear()
+	if bc.Get() != "" {
+		t.Fatal("Clear() should reset the value to the zero value of T")
+	}
+}
+
+func TestClose(t *testing.T) {
+
+	bc := bchan.New(2)
+	sub := bc.Subscribe()
+	bc.Bcast("bill")
+	bc.Close()
+	bc.Close() // idempotent
+
+	if _, ok := <-bc.Ch; ok {
+		t.Fatal("Close() should have drained and closed Ch")
+	}
+	bc.BcastAck() // must not panic after close
+	for range sub.Ch {
+		// Close must end subscriptions too
+	}
+	if _, err := bc.Recv(context.Background()); err != bchan.ErrClosed {
+		t.Fatalf("expected ErrClosed from Recv, got %v", err)
+	}
+	if !bc.IsClosed() {
+		t.Fatal("IsClosed() should report true")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Bcast after Close should panic")
+		}
+	}()
+	bc.Bcast("lyle")
+}
+
+func TestCur(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	if v, ok := bc.Cur(); ok || v != 0 {
+		t.Fatalf("fresh bc: expected 0, false; got %v, %v", v, ok)
+	}
+	bc.Set(3)
+	if v, ok := bc.Cur(); ok || v != 3 {
+		t.Fatalf("after Set: expected 3, false; got %v, %v", v, ok)
+	}
+	bc.On()
+	if v, ok := bc.Cur(); !ok || v != 3 {
+		t.Fatalf("after On: expected 3, true; got %v, %v", v, ok)
+	}
+	if len(bc.Ch) != cap(bc.Ch) {
+		t.Fatal("Cur() must not consume from Ch")
+	}
+}
+
+func BenchmarkGetParallel(b *testing.B) {
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(1)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			bc.Get()
+		}
+	})
+}
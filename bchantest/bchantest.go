@@ -0,0 +1,98 @@
+// package bchantest provides helpers for writing
+// deterministic tests against code that uses bchan.
+//
+// WaitForReceivers holds a test until its consumers
+// have attached, ExpectBroadcast checks what they
+// would be given, CheckGoroutines catches a
+// goroutine left running, and FakeClock, installed with
+// bchan.WithClock, lets a test step TTLs, debounce
+// windows and rate limits forward by hand instead
+// of sleeping. Sim and Explore go further, running
+// producer and consumer actors one step at a time so
+// that every interleaving of a protocol can be tried.
+package bchantest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+// Timeout bounds how long ExpectBroadcast
+// waits for a value.
+var Timeout = time.Second
+
+// WaitForReceivers blocks until b has at least n
+// consumers attached, counting registered receivers
+// (see bchan.RegisterReceiver) and subscriptions,
+// or until timeout passes, when it returns an error
+// saying how many there were. Anonymous receivers
+// of Ch cannot be counted.
+func WaitForReceivers[T any](b *bchan.Of[T], n int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		st := b.Stats()
+		have := st.Receivers + st.Subscriptions
+		if have >= n {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("bchantest: %d receivers after %v, want %d", have, timeout, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// ExpectBroadcast receives one value from b, as a
+// receiver would, acks it, and fails t unless it
+// equals want (by reflect.DeepEqual). It fails t if
+// nothing is broadcast within Timeout.
+func ExpectBroadcast[T any](t testing.TB, b *bchan.Of[T], want T) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	got, err := b.Recv(ctx)
+	if err != nil {
+		t.Fatalf("bchantest: expected broadcast of %v: %v", want, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("bchantest: got broadcast %v, want %v", got, want)
+	}
+}
+
+// ExpectNoBroadcast fails t if b has a value on
+// offer, or gets one within d.
+func ExpectNoBroadcast[T any](t testing.TB, b *bchan.Of[T], d time.Duration) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	got, err := b.Recv(ctx)
+	if err == nil {
+		t.Fatalf("bchantest: unexpected broadcast of %v", got)
+	}
+}
+
+// CheckGoroutines notes how many goroutines are
+// running and returns a func that fails t unless,
+// within Timeout, there are no more than that again.
+// Deferred, it catches a goroutine the code under
+// test left behind. It cannot tell whose goroutines
+// it counts, so it is not for parallel tests.
+func CheckGoroutines(t testing.TB) func() {
+	n := runtime.NumGoroutine()
+	return func() {
+		t.Helper()
+		deadline := time.Now().Add(Timeout)
+		for runtime.NumGoroutine() > n {
+			if time.Now().After(deadline) {
+				t.Fatalf("bchantest: %d goroutines still running, want at most %d", runtime.NumGoroutine(), n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package bchantest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/bchantest"
+)
+
+func TestWaitForReceivers(t *testing.T) {
+
+	b := bchan.NewOf[int](1)
+	defer b.Close()
+	if err := bchantest.WaitForReceivers(b, 1, 10*time.Millisecond); err == nil {
+		t.Fatal("expected a timeout with no receivers")
+	}
+	go func() {
+		b.RegisterReceiver("worker")
+		b.Subscribe()
+	}()
+	if err := bchantest.WaitForReceivers(b, 2, time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpectBroadcast(t *testing.T) {
+
+	b := bchan.NewOf[[]string](1)
+	defer b.Close()
+	bchantest.ExpectNoBroadcast(t, b, 10*time.Millisecond)
+	b.Bcast([]string{"a", "b"})
+	bchantest.ExpectBroadcast(t, b, []string{"a", "b"})
+	b.Off()
+	bchantest.ExpectNoBroadcast(t, b, 10*time.Millisecond)
+}
+
+func TestCheckGoroutines(t *testing.T) {
+
+	check := bchantest.CheckGoroutines(t)
+	done := make(chan struct{})
+	go func() {
+		<-done
+	}()
+	close(done)
+	check() // waits for the goroutine to end.
+}
@@ -0,0 +1,139 @@
+package bchantest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+// FakeClock is a bchan.Clock that only moves when
+// told to. Timers set on it fire during Advance, in
+// order of due time, each in its own goroutine as
+// the Clock interface asks, but one at a time:
+// Advance waits for each to return, or to set a
+// timer of its own on c, before firing the next, so
+// by the time Advance returns the Bchan has done
+// everything that was due. A timer func that goes
+// on to wait for c, as a BcastAfter held up by
+// SetBackpressure does, is left waiting rather
+// than waited for.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+
+	// armed, while a timer func runs, is closed
+	// by the next AfterFunc.
+	armed chan struct{}
+}
+
+var _ bchan.Clock = &FakeClock{}
+
+type fakeTimer struct {
+	c  *FakeClock
+	at time.Time
+	f  func()
+}
+
+// NewFakeClock returns a FakeClock reading start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns c's time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc arranges for f to be called once
+// c has been advanced by d.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) bchan.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{c: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	if c.armed != nil {
+		close(c.armed)
+		c.armed = nil
+	}
+	return t
+}
+
+// Advance moves c forward by d, firing
+// the timers that fall due on the way.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+	for {
+		c.mu.Lock()
+		t := c.next(end)
+		if t == nil {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+		if t.at.After(c.now) {
+			c.now = t.at
+		}
+		armed := make(chan struct{})
+		c.armed = armed
+		c.mu.Unlock()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			t.f()
+		}()
+		select {
+		case <-done:
+		case <-armed:
+		}
+		c.mu.Lock()
+		if c.armed == armed {
+			c.armed = nil
+		}
+		c.mu.Unlock()
+	}
+}
+
+// Pending counts the timers yet to fire.
+func (c *FakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// next removes and returns the earliest timer
+// due by end, or nil.
+// Caller must hold c.mu.
+func (c *FakeClock) next(end time.Time) *fakeTimer {
+	if len(c.timers) == 0 {
+		return nil
+	}
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].at.Before(c.timers[j].at)
+	})
+	t := c.timers[0]
+	if t.at.After(end) {
+		return nil
+	}
+	c.timers = c.timers[1:]
+	return t
+}
+
+func (t *fakeTimer) Stop() bool {
+	c := t.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, x := range c.timers {
+		if x == t {
+			c.timers = append(c.timers[:i:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package bchantest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/bchantest"
+)
+
+func TestFakeClockTTL(t *testing.T) {
+
+	clk := bchantest.NewFakeClock(time.Unix(0, 0))
+	b := bchan.NewOfWithOptions[string](bchan.WithClock(clk))
+	defer b.Close()
+
+	b.BcastTTL("leader", time.Minute)
+	clk.Advance(59 * time.Second)
+	if !b.IsOn() {
+		t.Fatal("expired early")
+	}
+	clk.Advance(time.Second)
+	if b.IsOn() || b.Get() != "" {
+		t.Fatal("TTL should have expired")
+	}
+	if clk.Pending() != 0 {
+		t.Fatalf("timers left: %d", clk.Pending())
+	}
+}
+
+func TestFakeClockDebounce(t *testing.T) {
+
+	clk := bchantest.NewFakeClock(time.Unix(0, 0))
+	b := bchan.NewOfWithOptions[int](bchan.WithClock(clk), bchan.WithDebounce(time.Second))
+	defer b.Close()
+
+	for i := 1; i <= 3; i++ {
+		b.Bcast(i)
+	}
+	bchantest.ExpectNoBroadcast(t, b, 10*time.Millisecond)
+	clk.Advance(time.Second)
+	bchantest.ExpectBroadcast(t, b, 3)
+	if b.Seq() != 1 {
+		t.Fatalf("expected one broadcast, seq=%d", b.Seq())
+	}
+}
+
+func TestFakeClockRateLimit(t *testing.T) {
+
+	clk := bchantest.NewFakeClock(time.Unix(0, 0))
+	b := bchan.NewOfWithOptions[int](bchan.WithClock(clk))
+	defer b.Close()
+	b.SetRateLimit(1, 1, true)
+
+	if err := b.TryBcast(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.TryBcast(2); err != bchan.ErrRateLimited {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	clk.Advance(time.Second)
+	if err := b.TryBcast(3); err != nil {
+		t.Fatalf("token should have refilled: %v", err)
+	}
+}
+
+func TestFakeClockStop(t *testing.T) {
+
+	clk := bchantest.NewFakeClock(time.Unix(0, 0))
+	fired := false
+	tm := clk.AfterFunc(time.Second, func() { fired = true })
+	if !tm.Stop() || tm.Stop() {
+		t.Fatal("Stop should report true just once")
+	}
+	clk.Advance(time.Hour)
+	if fired {
+		t.Fatal("stopped timer fired")
+	}
+	if got := clk.Now(); !got.Equal(time.Unix(3600, 0)) {
+		t.Fatalf("clock reads %v", got)
+	}
+}
+
+func TestFakeClockTimerWaitingOnClock(t *testing.T) {
+
+	clk := bchantest.NewFakeClock(time.Unix(0, 0))
+	b := bchan.NewOfWithOptions[int](bchan.WithClock(clk))
+	defer b.Close()
+	b.SetBackpressure(1, time.Second)
+	b.Bcast(1)
+
+	// the scheduled Bcast waits, on clk, for an ack.
+	b.BcastAfter(time.Minute, 2)
+	advanced := make(chan struct{})
+	go func() {
+		clk.Advance(time.Minute)
+		close(advanced)
+	}()
+	select {
+	case <-advanced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Advance waited on a timer func waiting on the clock")
+	}
+
+	<-b.Ch
+	b.BcastAck()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := b.Await(ctx, func(v int) bool { return v == 2 }); err != nil {
+		t.Fatalf("the ack did not let the scheduled Bcast through: %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package bchantest
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/glycerine/bchan"
+)
+
+// FillStep is the name Sim gives the step
+// that puts one owed copy of the value into Ch.
+const FillStep = "fill"
+
+// MaxSteps bounds the length of one run of a
+// Sim under Explore, so a livelock shows up as
+// an error rather than a hang.
+var MaxSteps = 1000
+
+// ErrDeadlock is returned, wrapped, by Run and
+// Explore when actors remain but none can move.
+var ErrDeadlock = errors.New("bchantest: deadlock")
+
+// Sim runs producer/consumer actors against a Bchan
+// one step at a time, under the control of a test.
+// Each actor is a func run on its own goroutine but
+// only ever let through one Bchan operation at a
+// time, when the test picks it; fills of Ch are
+// steps of their own (see bchan.WithStepFills). So
+// the test decides the order of deliveries, fills
+// and receiver wakeups, and Explore can try them all.
+//
+// Actors must reach the Bchan only through their
+// Actor, and must not block on anything else.
+type Sim[T any] struct {
+	B *bchan.Of[T]
+
+	// Trace lists the steps taken so far.
+	Trace []string
+
+	actors []*Actor[T]
+}
+
+// Actor is one simulated goroutine of a Sim.
+type Actor[T any] struct {
+	Name string
+	s    *Sim[T]
+
+	// next is the operation the actor
+	// is waiting to be let through for.
+	next  *simOp
+	moved chan struct{}
+	done  bool
+	kill  bool
+}
+
+type simOp struct {
+	name    string
+	recv    bool
+	release chan struct{}
+}
+
+// NewSim returns a Sim over a new Bchan made with
+// opts and bchan.WithStepFills.
+func NewSim[T any](opts ...bchan.Option) *Sim[T] {
+	opts = append(opts[:len(opts):len(opts)], bchan.WithStepFills())
+	return &Sim[T]{B: bchan.NewOfWithOptions[T](opts...)}
+}
+
+// Go adds an actor running fn. It runs fn as
+// far as its first Bchan operation.
+func (s *Sim[T]) Go(name string, fn func(a *Actor[T])) {
+	a := &Actor[T]{Name: name, s: s, moved: make(chan struct{})}
+	s.actors = append(s.actors, a)
+	go func() {
+		defer func() {
+			a.done = true
+			a.next = nil
+			close(a.moved)
+		}()
+		fn(a)
+	}()
+	<-a.moved
+}
+
+// Choices lists the steps that could be taken
+// now: the name of each actor that can move,
+// in the order added, then FillStep if a fill
+// is owed. It is empty once everything is done,
+// or if the actors are deadlocked.
+func (s *Sim[T]) Choices() []string {
+	var c []string
+	for _, a := range s.enabled() {
+		c = append(c, a.Name)
+	}
+	if s.B.FillsOwed() > 0 {
+		c = append(c, FillStep)
+	}
+	return c
+}
+
+func (s *Sim[T]) enabled() (en []*Actor[T]) {
+	for _, a := range s.actors {
+		if a.next == nil {
+			continue
+		}
+		if a.next.recv && len(s.B.Ch) == 0 && !s.B.IsClosed() {
+			continue
+		}
+		en = append(en, a)
+	}
+	return en
+}
+
+// Step takes the i-th of the current Choices.
+func (s *Sim[T]) Step(i int) {
+	en := s.enabled()
+	if i == len(en) && s.B.FillsOwed() > 0 {
+		s.B.StepFill()
+		s.Trace = append(s.Trace, FillStep)
+		return
+	}
+	if i < 0 || i >= len(en) {
+		panic(fmt.Sprintf("bchantest: no step %d", i))
+	}
+	a := en[i]
+	s.Trace = append(s.Trace, a.Name+": "+a.next.name)
+	a.moved = make(chan struct{})
+	close(a.next.release)
+	<-a.moved
+}
+
+// Done reports whether every actor has returned.
+func (s *Sim[T]) Done() bool {
+	for _, a := range s.actors {
+		if !a.done {
+			return false
+		}
+	}
+	return true
+}
+
+// Run steps s to the end, picking each time the
+// choice pick returns (from among n), and returns
+// an error wrapping ErrDeadlock if the actors get
+// stuck. It stops any actors left over either way.
+func (s *Sim[T]) Run(pick func(n int) int) error {
+	defer s.stop()
+	for steps := 0; ; steps++ {
+		n := len(s.Choices())
+		if n == 0 {
+			if s.Done() {
+				return nil
+			}
+			return fmt.Errorf("%w after %s", ErrDeadlock, s)
+		}
+		if steps == MaxSteps {
+			return fmt.Errorf("bchantest: over %d steps: %s", MaxSteps, s)
+		}
+		s.Step(pick(n))
+	}
+}
+
+// stop ends any actors still waiting to move.
+func (s *Sim[T]) stop() {
+	for _, a := range s.actors {
+		if a.next != nil {
+			a.kill = true
+			a.moved = make(chan struct{})
+			close(a.next.release)
+			<-a.moved
+		}
+	}
+}
+
+func (s *Sim[T]) String() string {
+	return "[" + strings.Join(s.Trace, ", ") + "]"
+}
+
+// Explore runs every interleaving of the Sims that
+// setup builds, depth first: each run starts from a
+// fresh Sim, made with opts as by NewSim, takes a
+// different sequence of choices, and ends with check.
+// It returns how many runs it made, and the first
+// error from a run or check, with the steps that led
+// to it.
+func Explore[T any](setup func(s *Sim[T]), check func(s *Sim[T]) error, opts ...bchan.Option) (runs int, err error) {
+	var path []int // choices made
+	var width []int
+	for {
+		s := NewSim[T](opts...)
+		setup(s)
+		depth := 0
+		err := s.Run(func(n int) int {
+			if depth == len(path) {
+				path = append(path, 0)
+				width = append(width, n)
+			}
+			i := path[depth]
+			depth++
+			return i
+		})
+		s.B.Close()
+		runs++
+		if err == nil {
+			err = check(s)
+			if err != nil {
+				err = fmt.Errorf("%w after %s", err, s)
+			}
+		}
+		if err != nil {
+			return runs, err
+		}
+		// next path: bump the deepest choice left.
+		path, width = path[:depth], width[:depth]
+		for len(path) > 0 && path[len(path)-1]+1 == width[len(width)-1] {
+			path, width = path[:len(path)-1], width[:len(width)-1]
+		}
+		if len(path) == 0 {
+			return runs, nil
+		}
+		path[len(path)-1]++
+	}
+}
+
+// wait parks a until the Sim lets op through.
+func (a *Actor[T]) wait(name string, recv bool) {
+	op := &simOp{name: name, recv: recv, release: make(chan struct{})}
+	a.next = op
+	close(a.moved)
+	<-op.release
+	a.next = nil
+	if a.kill {
+		runtime.Goexit()
+	}
+}
+
+// Bcast is b.Bcast(v), as one step.
+func (a *Actor[T]) Bcast(v T) {
+	a.wait("Bcast", false)
+	a.s.B.Bcast(v)
+}
+
+// Recv receives from Ch, as one step that can
+// only be taken once Ch has a value or is closed.
+// It reports false if Ch was closed.
+func (a *Actor[T]) Recv() (v T, ok bool) {
+	a.wait("Recv", true)
+	v, ok = <-a.s.B.Ch
+	return v, ok
+}
+
+// Ack is b.BcastAck(), as one step.
+func (a *Actor[T]) Ack() {
+	a.wait("Ack", false)
+	a.s.B.BcastAck()
+}
+
+// Do runs f, which may call anything on b, as
+// one step called name.
+func (a *Actor[T]) Do(name string, f func(b *bchan.Of[T])) {
+	a.wait(name, false)
+	f(a.s.B)
+}
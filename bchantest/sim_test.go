@@ -0,0 +1,110 @@
+package bchantest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/bchantest"
+)
+
+// a consumer that follows the ack rule sees the
+// producer's values in order, in every interleaving.
+func TestExploreInOrder(t *testing.T) {
+
+	runs, err := bchantest.Explore(func(s *bchantest.Sim[int]) {
+		s.Go("producer", func(a *bchantest.Actor[int]) {
+			a.Bcast(1)
+			a.Bcast(2)
+		})
+		s.Go("consumer", func(a *bchantest.Actor[int]) {
+			last := 0
+			for i := 0; i < 2; i++ {
+				v, _ := a.Recv()
+				if v < last {
+					panic(fmt.Sprintf("saw %d after %d", v, last))
+				}
+				last = v
+				a.Ack()
+			}
+		})
+	}, func(s *bchantest.Sim[int]) error {
+		if got := s.B.Get(); got != 2 {
+			return fmt.Errorf("ended on %d", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs < 2 {
+		t.Fatalf("expected several interleavings, got %d", runs)
+	}
+}
+
+// skipping the ack starves the consumer once
+// the buffered copies run out.
+func TestExploreFindsDeadlock(t *testing.T) {
+
+	_, err := bchantest.Explore(func(s *bchantest.Sim[string]) {
+		s.Go("producer", func(a *bchantest.Actor[string]) {
+			a.Bcast("v")
+		})
+		s.Go("consumer", func(a *bchantest.Actor[string]) {
+			for i := 0; i < 3; i++ {
+				a.Recv()
+			}
+		})
+	}, func(s *bchantest.Sim[string]) error { return nil })
+	if !errors.Is(err, bchantest.ErrDeadlock) {
+		t.Fatalf("expected a deadlock, got %v", err)
+	}
+}
+
+// with room for three copies, the same consumer
+// gets by without acking.
+func TestExploreOptions(t *testing.T) {
+
+	_, err := bchantest.Explore(func(s *bchantest.Sim[string]) {
+		s.Go("producer", func(a *bchantest.Actor[string]) {
+			a.Bcast("v")
+		})
+		s.Go("consumer", func(a *bchantest.Actor[string]) {
+			for i := 0; i < 3; i++ {
+				a.Recv()
+			}
+		})
+	}, func(s *bchantest.Sim[string]) error { return nil }, bchan.WithDiameter(3))
+	if err != nil {
+		t.Fatalf("diameter 3 holds three copies; got %v", err)
+	}
+}
+
+func TestSimStep(t *testing.T) {
+
+	s := bchantest.NewSim[int]()
+	defer s.B.Close()
+	var got []int
+	s.Go("consumer", func(a *bchantest.Actor[int]) {
+		v, _ := a.Recv()
+		got = append(got, v)
+		a.Ack()
+	})
+	if c := s.Choices(); len(c) != 0 {
+		t.Fatalf("nothing to receive yet, but choices %v", c)
+	}
+	s.B.Bcast(7)
+	if c := s.Choices(); len(c) != 1 || c[0] != bchantest.FillStep {
+		t.Fatalf("expected only a fill, got %v", c)
+	}
+	s.Step(0)
+	s.Step(0) // consumer receives
+	s.Step(0) // and acks
+	if !s.Done() || len(got) != 1 || got[0] != 7 {
+		t.Fatalf("unexpected outcome %v after %s", got, s)
+	}
+	if s.B.FillsOwed() != 2 {
+		t.Fatalf("the ack should leave a fill owed, not do it")
+	}
+}
@@ -0,0 +1,145 @@
+// package broker fans a Bchan out across a fleet through an
+// external message broker such as NATS or Redis pub/sub.
+//
+// A Bridge is the small piece of a broker client that
+// bchan needs. Publish relays every new value of a local
+// Bchan to a subject; Feed does the reverse, broadcasting
+// each message on a subject into a local Bchan, so
+// consumers on every host keep using Ch and BcastAck
+// unchanged. Values are encoded with a bchan.Codec,
+// one message each; bchan.JSONCodec suits consumers
+// written in other languages. Adapters live in
+// the natsbridge and redisbridge subpackages; Local is an
+// in-process Bridge for tests.
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/glycerine/bchan"
+)
+
+// Bridge is a publish/subscribe broker connection.
+type Bridge interface {
+	// Publish sends data to everyone subscribed
+	// to subject.
+	Publish(ctx context.Context, subject string, data []byte) error
+
+	// Subscribe calls fn with the data of each
+	// message later published to subject, until
+	// unsubscribe is called. Calls to fn for one
+	// subscription do not overlap.
+	Subscribe(ctx context.Context, subject string, fn func(data []byte)) (unsubscribe func() error, err error)
+
+	// Close releases the connection.
+	Close() error
+}
+
+// Publish relays b to subject on br: the current value
+// if broadcasting is on, then every later broadcast. It
+// blocks until ctx is done, b is closed (returning nil),
+// or a value fails to encode with c or to send.
+func Publish[T any](ctx context.Context, b *bchan.Of[T], br Bridge, subject string, c bchan.Codec) error {
+	var sent uint64
+	err := b.Follow(ctx, func(v T, seq uint64, on bool) error {
+		if !on || seq == sent {
+			return nil
+		}
+		data, err := bchan.Marshal(c, v)
+		if err != nil {
+			return err
+		}
+		sent = seq
+		return br.Publish(ctx, subject, data)
+	})
+	if errors.Is(err, bchan.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// Feed broadcasts each message on subject into into,
+// until stop is called. Messages are decoded with c,
+// which must match the publisher's; those that do not
+// decode as a T are skipped, as are all once into is
+// closed. Call stop even then.
+func Feed[T any](ctx context.Context, br Bridge, subject string, into *bchan.Of[T], c bchan.Codec) (stop func() error, err error) {
+	return br.Subscribe(ctx, subject, func(data []byte) {
+		var v T
+		if bchan.Unmarshal(c, data, &v) != nil {
+			return
+		}
+		into.ApplyState(v, true, true) // ErrClosed once into is.
+	})
+}
+
+// Local is a Bridge whose broker is in-process: what is
+// published on it is delivered to its own subscribers.
+// It stands in for a real broker in tests.
+type Local struct {
+	mu     sync.Mutex
+	subs   map[string]map[*localSub]bool
+	closed bool
+}
+
+type localSub struct {
+	mu sync.Mutex
+	fn func(data []byte)
+}
+
+// NewLocal makes an empty Local.
+func NewLocal() *Local {
+	return &Local{subs: make(map[string]map[*localSub]bool)}
+}
+
+// Publish delivers data to subject's subscribers
+// before returning.
+func (l *Local) Publish(ctx context.Context, subject string, data []byte) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return errors.New("broker: Local is closed")
+	}
+	var subs []*localSub
+	for s := range l.subs[subject] {
+		subs = append(subs, s)
+	}
+	l.mu.Unlock()
+	for _, s := range subs {
+		s.mu.Lock()
+		s.fn(append([]byte(nil), data...))
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Subscribe adds fn as a subscriber to subject.
+func (l *Local) Subscribe(ctx context.Context, subject string, fn func(data []byte)) (func() error, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, errors.New("broker: Local is closed")
+	}
+	s := &localSub{fn: fn}
+	if l.subs[subject] == nil {
+		l.subs[subject] = make(map[*localSub]bool)
+	}
+	l.subs[subject][s] = true
+	return func() error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.subs[subject], s)
+		return nil
+	}, nil
+}
+
+// Close drops all subscribers; later calls fail.
+func (l *Local) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	l.subs = nil
+	return nil
+}
@@ -0,0 +1,76 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/broker"
+)
+
+func TestPublishFeed(t *testing.T) {
+
+	br := broker.NewLocal()
+	defer br.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// two hosts' worth of local Bchans.
+	var hosts []*bchan.Of[string]
+	for i := 0; i < 2; i++ {
+		h := bchan.NewOf[string](1)
+		stop, err := broker.Feed(ctx, br, "config", h, bchan.JSONCodec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stop()
+		hosts = append(hosts, h)
+	}
+
+	src := bchan.NewOf[string](1)
+	done := make(chan error, 1)
+	go func() { done <- broker.Publish(ctx, src, br, "config", bchan.JSONCodec) }()
+	src.Bcast("v1")
+
+	for i, h := range hosts {
+		select {
+		case v := <-h.Ch:
+			h.BcastAck()
+			if v != "v1" {
+				t.Fatalf("host %d got %q, want v1", i, v)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("host %d got nothing", i)
+		}
+	}
+
+	src.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("Publish returned %v once its Bchan closed, want nil", err)
+	}
+}
+
+func TestFeedIntoClosed(t *testing.T) {
+
+	br := broker.NewLocal()
+	defer br.Close()
+	ctx := context.Background()
+
+	h := bchan.NewOf[string](1)
+	stop, err := broker.Feed(ctx, br, "config", h, bchan.JSONCodec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	h.Close()
+
+	data, err := bchan.Marshal(bchan.JSONCodec, "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// delivered to the closed h: skipped, not a panic.
+	if err := br.Publish(ctx, "config", data); err != nil {
+		t.Fatal(err)
+	}
+}
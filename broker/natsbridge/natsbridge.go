@@ -0,0 +1,47 @@
+// package natsbridge adapts a NATS connection
+// to broker.Bridge.
+package natsbridge
+
+import (
+	"context"
+
+	"github.com/glycerine/bchan/broker"
+	"github.com/nats-io/nats.go"
+)
+
+// Bridge is a broker.Bridge over core NATS
+// publish/subscribe.
+type Bridge struct {
+	nc *nats.Conn
+}
+
+var _ broker.Bridge = &Bridge{}
+
+// New wraps nc. Closing the Bridge closes nc.
+func New(nc *nats.Conn) *Bridge {
+	return &Bridge{nc: nc}
+}
+
+// Publish sends data on subject.
+func (b *Bridge) Publish(ctx context.Context, subject string, data []byte) error {
+	return b.nc.Publish(subject, data)
+}
+
+// Subscribe calls fn for each message on subject.
+// NATS runs the calls for one subscription in order
+// on a goroutine of its own.
+func (b *Bridge) Subscribe(ctx context.Context, subject string, fn func(data []byte)) (func() error, error) {
+	sub, err := b.nc.Subscribe(subject, func(m *nats.Msg) {
+		fn(m.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub.Unsubscribe, nil
+}
+
+// Close closes the NATS connection.
+func (b *Bridge) Close() error {
+	b.nc.Close()
+	return nil
+}
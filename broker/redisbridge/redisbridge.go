@@ -0,0 +1,52 @@
+// package redisbridge adapts a Redis client
+// to broker.Bridge, using Redis pub/sub.
+package redisbridge
+
+import (
+	"context"
+
+	"github.com/glycerine/bchan/broker"
+	"github.com/redis/go-redis/v9"
+)
+
+// Bridge is a broker.Bridge over Redis
+// PUBLISH and SUBSCRIBE.
+type Bridge struct {
+	rdb *redis.Client
+}
+
+var _ broker.Bridge = &Bridge{}
+
+// New wraps rdb. Closing the Bridge closes rdb.
+func New(rdb *redis.Client) *Bridge {
+	return &Bridge{rdb: rdb}
+}
+
+// Publish sends data on the channel named subject.
+func (b *Bridge) Publish(ctx context.Context, subject string, data []byte) error {
+	return b.rdb.Publish(ctx, subject, data).Err()
+}
+
+// Subscribe calls fn, from one goroutine, for each
+// message on the channel named subject. It returns
+// once Redis has confirmed the subscription, so
+// nothing published afterwards is missed.
+func (b *Bridge) Subscribe(ctx context.Context, subject string, fn func(data []byte)) (func() error, error) {
+	ps := b.rdb.Subscribe(ctx, subject)
+	if _, err := ps.Receive(ctx); err != nil {
+		ps.Close()
+		return nil, err
+	}
+	ch := ps.Channel()
+	go func() {
+		for m := range ch {
+			fn([]byte(m.Payload))
+		}
+	}()
+	return ps.Close, nil
+}
+
+// Close closes the Redis client.
+func (b *Bridge) Close() error {
+	return b.rdb.Close()
+}
@@ -0,0 +1,81 @@
+package bchan
+
+import (
+	"reflect"
+)
+
+// SetEqual sets the equality test used by
+// BcastIfChanged. nil restores the default,
+// reflect.DeepEqual.
+func (b *Of[T]) SetEqual(eq func(a, b T) bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.equal = eq
+}
+
+// BcastIfChanged is Bcast(val) unless broadcasting
+// is already on with a value equal to val, in which
+// case it does nothing, so receivers are not woken
+// for identical state. It reports whether val was
+// broadcast. The latest pending value, if Bcast is
+// being debounced or rate limited, is what val is
+// compared against.
+func (b *Of[T]) BcastIfChanged(val T) bool {
+	b.lockForBcast()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("BcastIfChanged") {
+		return false
+	}
+	if b.sameAsLatest(val) {
+		return false
+	}
+	return b.bcast("BcastIfChanged", val) == nil
+}
+
+// sameAsLatest says whether val equals the value
+// that is, or is about to be, broadcast.
+// Caller must hold b.mu.
+func (b *Of[T]) sameAsLatest(val T) bool {
+	if b.hasPending {
+		return b.eq(b.pending, val)
+	}
+	return b.on && b.live && b.eq(b.cur, val)
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) eq(x, y T) bool {
+	if b.equal != nil {
+		return b.equal(x, y)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// CompareAndBcast broadcasts new only if the current
+// value still equals old, as judged by SetEqual's
+// test, and reports whether it did. Like
+// atomic.CompareAndSwap, this lets several producers
+// advance shared state without losing updates:
+// a producer whose view is stale gets false and can
+// re-read with Get and retry. A pending debounced or
+// rate-limited value counts as current. The broadcast
+// itself is immediate, never debounced; it is refused,
+// reporting false, if it is over the rate limit or
+// held back by SetBackpressure.
+func (b *Of[T]) CompareAndBcast(old, new T) bool {
+	b.lockForBcast()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("CompareAndBcast") {
+		return false
+	}
+	latest := b.cur
+	if b.hasPending {
+		latest = b.pending
+	}
+	if !b.eq(latest, old) || b.admitNow("CompareAndBcast", &new, false) != nil {
+		return false
+	}
+	b.cancelPending()
+	b.store("CompareAndBcast", new)
+	b.activate()
+	return true
+}
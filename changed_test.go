@@ -0,0 +1,70 @@
+package bchan_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestBcastIfChanged(t *testing.T) {
+
+	bc := bchan.NewOf[[]int](1)
+	if !bc.BcastIfChanged([]int{1, 2}) {
+		t.Fatal("first value is always a change")
+	}
+	<-bc.Ch // leave a gap that a refill would close
+	if bc.BcastIfChanged([]int{1, 2}) {
+		t.Fatal("deeply equal value should be skipped")
+	}
+	if len(bc.Ch) != 1 {
+		t.Fatal("a skipped BcastIfChanged must not drain/refill")
+	}
+	if !bc.BcastIfChanged([]int{1, 2, 3}) || bc.Seq() != 2 {
+		t.Fatal("a different value should be broadcast")
+	}
+
+	bc.Clear()
+	if !bc.BcastIfChanged(nil) {
+		t.Fatal("when off, BcastIfChanged should broadcast")
+	}
+}
+
+func TestWithEqual(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[string](bchan.WithEqual(strings.EqualFold))
+	bc.Bcast("Bill")
+	if bc.BcastIfChanged("BILL") {
+		t.Fatal("case-insensitive equality should have skipped BILL")
+	}
+}
+
+func TestCompareAndBcast(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for {
+					cur := bc.Get()
+					if bc.CompareAndBcast(cur, cur+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v := bc.Get(); v != 801 {
+		t.Fatalf("expected no lost increments, got %v", v)
+	}
+	if bc.CompareAndBcast(5, 6) {
+		t.Fatal("stale old value should fail")
+	}
+}
@@ -0,0 +1,70 @@
+package bchan
+
+import (
+	"time"
+)
+
+// Clock is where a Bchan gets the time for all its
+// timed features: TTLs, debouncing, rate limits,
+// heartbeats, idle timeouts, scheduled broadcasts,
+// ack checking and deadlines, the timeouts of the
+// waiting calls, and the times it stamps on values
+// and reports. The default is the system clock,
+// which is also what testing/synctest fakes inside
+// a bubble; see WithClock or SetClock to swap in
+// another, such as bchantest.FakeClock.
+type Clock interface {
+	Now() time.Time
+
+	// AfterFunc calls f in its own goroutine
+	// once d has passed, as time.AfterFunc does.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending AfterFunc call.
+type Timer interface {
+	// Stop prevents the call, reporting
+	// false if it already happened or
+	// was stopped before.
+	Stop() bool
+}
+
+// WithClock has b take its time from c;
+// nil means the system clock.
+func WithClock(c Clock) Option {
+	return func(cfg *config) {
+		cfg.clock = c
+	}
+}
+
+// SetClock is WithClock for a Bchan already made.
+// Call it before b is put to use: timers already
+// running stay on the clock they were set on.
+func (b *Of[T]) SetClock(c Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = c
+}
+
+// now tells the time by b's clock.
+func (b *Of[T]) now() time.Time {
+	if b.clock == nil {
+		return time.Now()
+	}
+	return b.clock.Now()
+}
+
+// afterFunc is AfterFunc on b's clock.
+func (b *Of[T]) afterFunc(d time.Duration, f func()) Timer {
+	if b.clock == nil {
+		return time.AfterFunc(d, f)
+	}
+	return b.clock.AfterFunc(d, f)
+}
+
+// timeout returns a channel closed once d has
+// passed on b's clock, and the Timer to stop it.
+func (b *Of[T]) timeout(d time.Duration) (<-chan struct{}, Timer) {
+	due := make(chan struct{})
+	return due, b.afterFunc(d, func() { close(due) })
+}
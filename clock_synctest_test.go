@@ -0,0 +1,63 @@
+//go:build go1.25
+
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSynctestTTLAndDebounce(t *testing.T) {
+
+	synctest.Test(t, func(t *testing.T) {
+		bc := bchan.NewOf[string](1)
+		defer bc.Close()
+		bc.BcastTTL("leader", time.Hour)
+		time.Sleep(time.Hour)
+		synctest.Wait()
+		if bc.IsOn() {
+			t.Fatal("TTL did not expire under synctest")
+		}
+
+		bc.SetDebounce(time.Minute)
+		bc.Bcast("a")
+		bc.Bcast("b")
+		time.Sleep(time.Minute)
+		synctest.Wait()
+		if !bc.IsOn() || bc.Get() != "b" {
+			t.Fatalf("debounced value not flushed: on=%v val=%q", bc.IsOn(), bc.Get())
+		}
+	})
+}
+
+func TestSynctestHeartbeatAndDeadline(t *testing.T) {
+
+	synctest.Test(t, func(t *testing.T) {
+		bc := bchan.NewEnveloped[int](1)
+		defer bc.Close()
+		var slow []bchan.SlowAck
+		bc.SetAckDeadline(time.Second, func(s bchan.SlowAck) { slow = append(slow, s) })
+		bc.SetHeartbeat(time.Minute)
+		bchan.BcastVal(bc, 1)
+		at := bc.Envelope().At
+
+		time.Sleep(time.Minute)
+		synctest.Wait()
+		if got := bc.Envelope().Fresh.Sub(at); got != time.Minute {
+			t.Fatalf("heartbeat refreshed after %v, want 1m", got)
+		}
+
+		if _, err := bc.RecvChecked(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * time.Second)
+		bc.BcastAck()
+		if len(slow) != 1 || slow[0].Held != 2*time.Second {
+			t.Fatalf("expected one slow ack held 2s, got %+v", slow)
+		}
+	})
+}
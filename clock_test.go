@@ -0,0 +1,44 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+// stoppedClock never moves and never fires.
+type stoppedClock struct{ at time.Time }
+
+type stoppedTimer struct{}
+
+func (c stoppedClock) Now() time.Time { return c.at }
+
+func (c stoppedClock) AfterFunc(d time.Duration, f func()) bchan.Timer {
+	return stoppedTimer{}
+}
+
+func (stoppedTimer) Stop() bool { return true }
+
+func TestSetClock(t *testing.T) {
+
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bc := bchan.NewEnveloped[int](1)
+	defer bc.Close()
+	bc.SetClock(stoppedClock{at})
+	bchan.BcastVal(bc, 1)
+	if got := bc.Envelope().At; !got.Equal(at) {
+		t.Fatalf("value stamped %v, want %v", got, at)
+	}
+	if age := bc.Get().Age(); age != 0 {
+		t.Fatalf("Age went by the system clock: %v", age)
+	}
+	if bc.Envelope().Stale(time.Nanosecond) {
+		t.Fatal("Stale went by the system clock")
+	}
+	bc.BcastTTL(bchan.EnvelopeOf[int]{Val: 2}, time.Nanosecond)
+	time.Sleep(10 * time.Millisecond)
+	if !bc.IsOn() {
+		t.Fatal("TTL expired by the system clock")
+	}
+}
@@ -0,0 +1,125 @@
+package bchan
+
+import (
+	"reflect"
+)
+
+// SetClone makes b hand every receiver its own copy
+// of the value, made by fn. The value given to Set
+// or Bcast is copied as it is stored, so the
+// producer may go on changing its original; then
+// each value placed into Ch, a consumer group's Ch
+// or a subscription is a fresh copy, so a receiver
+// may change what it gets without racing the others.
+// Use it when the payload is a map, slice or pointer
+// that someone will mutate. DeepCopy is a reflection
+// based fn that does for most types; a hand-written
+// one is faster. SetClone(nil) turns copying off.
+// Get and Cur still return b's own copy, which must
+// not be mutated.
+func (b *Of[T]) SetClone(fn func(v T) T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clone = fn
+	b.rebuildOut()
+}
+
+// WithClone is SetClone(fn) from the start. fn
+// must be a func(T) T for the T of the Bchan being
+// made; a nil fn means DeepCopy.
+func WithClone[T any](fn func(v T) T) Option {
+	return func(c *config) {
+		if fn == nil {
+			fn = DeepCopy[T]
+		}
+		c.clone = fn
+	}
+}
+
+// copy returns a copy of v, or v itself
+// if b is not copying.
+func (b *Of[T]) copy(v T) T {
+	if b.clone == nil {
+		return v
+	}
+	return b.clone(v)
+}
+
+// DeepCopy returns a copy of v that shares no
+// memory reachable through pointers, maps, slices
+// or interfaces with it, found by reflection.
+// Unexported struct fields, channels and funcs
+// are copied shallowly.
+func DeepCopy[T any](v T) T {
+	if rv := reflect.ValueOf(v); !rv.IsValid() || rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return v // nil: nothing to copy.
+	}
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	deepCopy(dst, src, make(map[uintptr]reflect.Value))
+	return dst.Interface().(T)
+}
+
+// deepCopy copies src into the settable dst. seen
+// maps pointers already copied to their copies,
+// so shared and cyclic structures come out the same
+// shape.
+func deepCopy(dst, src reflect.Value, seen map[uintptr]reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if p, ok := seen[src.Pointer()]; ok && p.Type() == src.Type() {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		seen[src.Pointer()] = p
+		deepCopy(p.Elem(), src.Elem(), seen)
+		dst.Set(p)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		e := reflect.New(src.Elem().Type()).Elem()
+		deepCopy(e, src.Elem(), seen)
+		dst.Set(e)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		it := src.MapRange()
+		for it.Next() {
+			k := reflect.New(src.Type().Key()).Elem()
+			deepCopy(k, it.Key(), seen)
+			e := reflect.New(src.Type().Elem()).Elem()
+			deepCopy(e, it.Value(), seen)
+			m.SetMapIndex(k, e)
+		}
+		dst.Set(m)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(s.Index(i), src.Index(i), seen)
+		}
+		dst.Set(s)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i), seen)
+		}
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if f := dst.Field(i); f.CanSet() {
+				deepCopy(f, src.Field(i), seen)
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
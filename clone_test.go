@@ -0,0 +1,79 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSetClone(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[map[string]int](bchan.WithDiameter(2), bchan.WithClone[map[string]int](nil))
+	m := map[string]int{"a": 1}
+	bc.Bcast(m)
+	m["a"] = 100 // the producer's copy only
+
+	r1 := <-bc.Ch
+	r2 := <-bc.Ch
+	bc.BcastAck()
+	bc.BcastAck()
+	r1["a"] = 2 // one receiver's copy only
+	if r1["a"] == r2["a"] || r2["a"] != 1 {
+		t.Fatalf("receivers share a map: %v %v", r1, r2)
+	}
+
+	sub := bc.Subscribe()
+	defer bc.Unsubscribe(sub)
+	s1 := <-sub.Ch
+	s1["a"] = 3
+	if s2 := <-sub.Ch; s2["a"] != 1 {
+		t.Fatalf("subscription handed out a shared map: %v", s2)
+	}
+
+	bc.SetClone(nil)
+	x := <-bc.Ch
+	bc.BcastAck()
+	if y := <-bc.Ch; x["a"] != y["a"] {
+		t.Fatal("values should be shared once copying is off")
+	}
+	bc.BcastAck()
+}
+
+func TestDeepCopy(t *testing.T) {
+
+	type node struct {
+		Name string
+		Kids []*node
+		Tags map[string][]int
+		Any  interface{}
+		Next *node
+	}
+	leaf := &node{Name: "leaf"}
+	root := &node{Name: "root", Kids: []*node{leaf, leaf}, Tags: map[string][]int{"x": {1}}, Any: []string{"s"}}
+	root.Next = root // a cycle
+
+	cp := bchan.DeepCopy(root)
+	if cp == root || cp.Kids[0] == leaf || cp.Next != cp || cp.Kids[0] != cp.Kids[1] {
+		t.Fatal("copy does not have the original's shape in fresh memory")
+	}
+	cp.Tags["x"][0] = 2
+	cp.Any.([]string)[0] = "t"
+	if root.Tags["x"][0] != 1 || root.Any.([]string)[0] != "s" {
+		t.Fatal("copy shares memory with the original")
+	}
+}
+
+func TestDeepCopyNil(t *testing.T) {
+
+	bc := bchan.NewWithOptions(bchan.WithClone[interface{}](nil))
+	defer bc.Close()
+	bc.Bcast(nil)
+	if v := <-bc.Ch; v != nil {
+		t.Fatalf("expected nil, got %v", v)
+	}
+	bc.BcastAck()
+	var p *int
+	if bchan.DeepCopy(p) != nil {
+		t.Fatal("a nil pointer should copy as nil")
+	}
+}
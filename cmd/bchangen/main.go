@@ -0,0 +1,183 @@
+// Command bchangen writes a named wrapper around a
+// bchan.Of for one payload type, for code that
+// would rather pass a plain type than an
+// instantiated generic one through its APIs.
+// Use it from go:generate:
+//
+//	//go:generate bchangen -type Config
+//
+// which writes bchanconfig_bchan.go in the current
+// package, defining BchanConfig with a
+// NewBchanConfig constructor and Bcast, Set, Get,
+// Recv, Ch, BcastAck and friends taking and
+// returning Config. Flags:
+//
+//	-type     the payload type, as written in the package (required)
+//	-name     the wrapper's name (default Bchan + the type's name)
+//	-package  the package clause (default $GOPACKAGE)
+//	-import   an import path the type needs, if any
+//	-o        the output file (default <name>_bchan.go, lower case)
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+type config struct {
+	Type    string
+	Name    string
+	Package string
+	Import  string
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("bchangen: ")
+	var cfg config
+	var out string
+	flag.StringVar(&cfg.Type, "type", "", "payload type")
+	flag.StringVar(&cfg.Name, "name", "", "wrapper type name")
+	flag.StringVar(&cfg.Package, "package", os.Getenv("GOPACKAGE"), "package clause")
+	flag.StringVar(&cfg.Import, "import", "", "import path the type needs")
+	flag.StringVar(&out, "o", "", "output file")
+	flag.Parse()
+	if cfg.Type == "" || cfg.Package == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName(cfg.Type)
+	}
+	if out == "" {
+		out = strings.ToLower(cfg.Name) + "_bchan.go"
+	}
+	src, err := generate(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// defaultName is Bchan followed by typ's name,
+// capitalized, without any package or pointer:
+// Bchan for string is BchanString, and for
+// *pkg.Config is BchanConfig.
+func defaultName(typ string) string {
+	name := strings.TrimLeft(typ, "*[]")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	r := []rune(name)
+	if len(r) > 0 {
+		r[0] = unicode.ToUpper(r[0])
+	}
+	return "Bchan" + string(r)
+}
+
+// generate returns the gofmt-ed wrapper source.
+func generate(cfg config) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := wrapper.Execute(&buf, cfg); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+var wrapper = template.Must(template.New("wrapper").Parse(`// Code generated by bchangen -type {{.Type}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/glycerine/bchan"
+{{- if .Import}}
+	"{{.Import}}"
+{{- end}}
+)
+
+// {{.Name}} is a bchan.Of[{{.Type}}].
+type {{.Name}} struct {
+	b *bchan.Of[{{.Type}}]
+}
+
+// New{{.Name}} makes a {{.Name}}; see bchan.NewOf.
+func New{{.Name}}(expectedDiameter int) *{{.Name}} {
+	return &{{.Name}}{b: bchan.NewOf[{{.Type}}](expectedDiameter)}
+}
+
+// Unwrap returns the underlying bchan.Of.
+func (w *{{.Name}}) Unwrap() *bchan.Of[{{.Type}}] {
+	return w.b
+}
+
+// Ch is bchan.Of.RecvCh. Call BcastAck after
+// every receive from it.
+func (w *{{.Name}}) Ch() <-chan {{.Type}} {
+	return w.b.RecvCh()
+}
+
+// BcastAck is bchan.Of.BcastAck.
+func (w *{{.Name}}) BcastAck() {
+	w.b.BcastAck()
+}
+
+// Bcast is bchan.Of.Bcast.
+func (w *{{.Name}}) Bcast(v {{.Type}}) {
+	w.b.Bcast(v)
+}
+
+// Set is bchan.Of.Set.
+func (w *{{.Name}}) Set(v {{.Type}}) {
+	w.b.Set(v)
+}
+
+// Get is bchan.Of.Get.
+func (w *{{.Name}}) Get() {{.Type}} {
+	return w.b.Get()
+}
+
+// Recv is bchan.Of.Recv.
+func (w *{{.Name}}) Recv(ctx context.Context) ({{.Type}}, error) {
+	return w.b.Recv(ctx)
+}
+
+// On is bchan.Of.On.
+func (w *{{.Name}}) On() {
+	w.b.On()
+}
+
+// Off is bchan.Of.Off.
+func (w *{{.Name}}) Off() {
+	w.b.Off()
+}
+
+// IsOn is bchan.Of.IsOn.
+func (w *{{.Name}}) IsOn() bool {
+	return w.b.IsOn()
+}
+
+// Clear is bchan.Of.Clear.
+func (w *{{.Name}}) Clear() {
+	w.b.Clear()
+}
+
+// Close is bchan.Of.Close.
+func (w *{{.Name}}) Close() {
+	w.b.Close()
+}
+`))
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+
+	src, err := generate(config{Type: "*cfg.Config", Name: defaultName("*cfg.Config"), Package: "app", Import: "example.com/cfg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "x.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"package app\n",
+		`"example.com/cfg"`,
+		"type BchanConfig struct",
+		"func (w *BchanConfig) Bcast(v *cfg.Config) {",
+		"func (w *BchanConfig) Recv(ctx context.Context) (*cfg.Config, error) {",
+		"func (w *BchanConfig) Ch() <-chan *cfg.Config {",
+		"func (w *BchanConfig) BcastAck() {",
+		"b *bchan.Of[*cfg.Config]",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code lacks %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestDefaultName(t *testing.T) {
+
+	for typ, want := range map[string]string{
+		"string":       "BchanString",
+		"*pkg.Config":  "BchanConfig",
+		"[]byte":       "BchanByte",
+		"config.state": "BchanState",
+	} {
+		if got := defaultName(typ); got != want {
+			t.Errorf("defaultName(%q) = %q, want %q", typ, got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package bchan
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// Codec is how values are serialized wherever they
+// leave memory: snapshots (MarshalBinary), the
+// journal, and the network bridge packages. GobCodec
+// and JSONCodec are provided; to carry protobuf or
+// msgpack payloads, implement Codec over that
+// library's stream encoder and decoder.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder writes one value per call to Encode,
+// framed so that the matching Decoder can find
+// where it ends.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Decoder reads one value per call to Decode,
+// returning io.EOF at a clean end of stream.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+var (
+	// GobCodec is the encoding/gob Codec. With a
+	// plain Bchan, the concrete types of values
+	// must be gob.Register-ed on both sides.
+	GobCodec Codec = gobCodec{}
+
+	// JSONCodec is the encoding/json Codec, one
+	// value per line. Decoding into a plain Bchan
+	// gives back generic JSON values, so prefer a
+	// concrete T with it.
+	JSONCodec Codec = jsonCodec{}
+)
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
+func (gobCodec) NewDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
+func (jsonCodec) NewDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
+
+// Marshal encodes v alone with c, for
+// transports that carry discrete messages.
+func Marshal(c Codec, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes data made by Marshal into v.
+func Unmarshal(c Codec, data []byte, v interface{}) error {
+	return c.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// SetCodec makes c the Codec for b's snapshots and
+// journal. Unless set, snapshots use GobCodec and
+// the journal JSONCodec.
+func (b *Of[T]) SetCodec(c Codec) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.codec = c
+}
+
+// codecOr is b's Codec, or def if none is set.
+// Caller must hold b.mu.
+func (b *Of[T]) codecOr(def Codec) Codec {
+	if b.codec != nil {
+		return b.codec
+	}
+	return def
+}
@@ -0,0 +1,49 @@
+package bchan_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestCodec(t *testing.T) {
+
+	type point struct{ X, Y int }
+	for _, c := range []bchan.Codec{bchan.GobCodec, bchan.JSONCodec} {
+		data, err := bchan.Marshal(c, point{1, 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var p point
+		if err := bchan.Unmarshal(c, data, &p); err != nil || p != (point{1, 2}) {
+			t.Fatalf("%T round trip got %v, %v", c, p, err)
+		}
+	}
+}
+
+func TestSetCodec(t *testing.T) {
+
+	// a gob journal and a JSON snapshot, the
+	// reverse of the defaults.
+	var buf bytes.Buffer
+	src := bchan.NewOfWithOptions[string](bchan.WithCodec(bchan.GobCodec), bchan.WithJournal(&buf))
+	src.Bcast("v1")
+	dst := bchan.NewOfWithOptions[string](bchan.WithCodec(bchan.GobCodec))
+	if err := dst.ReplayJournal(context.Background(), &buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, on := dst.Cur(); !on || v != "v1" {
+		t.Fatalf("replayed gob journal gave %q on=%v", v, on)
+	}
+
+	src.SetCodec(bchan.JSONCodec)
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte(`"v1"`)) {
+		t.Fatalf("snapshot %q is not JSON", data)
+	}
+}
@@ -0,0 +1,53 @@
+package bchan
+
+import (
+	"context"
+	"sync"
+)
+
+// RecvAfter blocks until b is broadcasting a value
+// whose sequence number (see Seq) is greater than
+// after, and returns it with that number. Pass 0 to
+// take the current value, then each seq returned to
+// wait for the one after it; values that come and
+// go between calls are skipped. No BcastAck is
+// needed. RecvAfter returns ctx.Err() if ctx is done
+// first, and ErrClosed once b is closed.
+//
+// RecvAfter waits on a sync.Cond rather than on Ch,
+// so any number of receivers cost b nothing but
+// their wakeups; it is how receivers must read a
+// Bchan made WithCondBackend.
+func (b *Of[T]) RecvAfter(ctx context.Context, after uint64) (val T, seq uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cond := b.recvCond()
+	stop := context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		cond.Broadcast()
+	})
+	defer stop()
+	for {
+		if b.closed {
+			return val, 0, ErrClosed
+		}
+		if b.on && !b.paused && b.seq > after {
+			return b.handout(b.cur), b.seq, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return val, 0, err
+		}
+		cond.Wait()
+	}
+}
+
+// recvCond returns b's sync.Cond, making it
+// on first use.
+// Caller must hold b.mu.
+func (b *Of[T]) recvCond() *sync.Cond {
+	if b.cond == nil {
+		b.cond = sync.NewCond(&b.mu)
+	}
+	return b.cond
+}
@@ -0,0 +1,77 @@
+package bchan_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestCondBackend(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[int](bchan.WithCondBackend(), bchan.WithDiameter(10000))
+	if cap(bc.Ch) != 0 {
+		t.Fatalf("cond backend allocated a Ch of cap %d", cap(bc.Ch))
+	}
+
+	const n = 1000
+	var wg sync.WaitGroup
+	got := make([]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var seq uint64
+			for {
+				v, s, err := bc.RecvAfter(context.Background(), seq)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				seq = s
+				if v == 3 {
+					got[i] = v
+					return
+				}
+			}
+		}(i)
+	}
+	for v := 1; v <= 3; v++ {
+		bc.Bcast(v)
+	}
+	wg.Wait()
+	for i, v := range got {
+		if v != 3 {
+			t.Fatalf("receiver %d ended on %d", i, v)
+		}
+	}
+	if len(bc.Ch) != 0 {
+		t.Fatal("cond backend sent on Ch")
+	}
+}
+
+func TestRecvAfter(t *testing.T) {
+
+	bc := bchan.NewOf[string](1)
+	bc.Bcast("a")
+	v, seq, err := bc.RecvAfter(context.Background(), 0)
+	if err != nil || v != "a" || seq != 1 {
+		t.Fatalf("got %q, %d, %v; want a, 1, nil", v, seq, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := bc.RecvAfter(ctx, seq); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bc.Close()
+	}()
+	if _, _, err := bc.RecvAfter(context.Background(), seq); err != bchan.ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
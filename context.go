@@ -0,0 +1,39 @@
+package bchan
+
+import (
+	"context"
+)
+
+// NewWithContext makes a Bchan whose lifetime is
+// tied to ctx: when ctx is done, the Bchan is
+// Closed, so receivers see Ch closed and Recv
+// returns ErrClosed. Unlike a Bchan closed by hand,
+// one torn down this way rejects later broadcasts
+// quietly instead of panicking, since producers
+// cannot easily avoid racing with cancellation:
+// Bcast, Set, and On do nothing, and TryBcast and
+// BcastAndWait return ErrClosed.
+func NewWithContext(ctx context.Context, expectedDiameter int) *Bchan {
+	return NewOfWithContext[interface{}](ctx, expectedDiameter)
+}
+
+// NewOfWithContext is the type-parameterized NewWithContext.
+func NewOfWithContext[T any](ctx context.Context, expectedDiameter int) *Of[T] {
+	b := NewOf[T](expectedDiameter)
+	b.quiet = true
+	w, _ := b.watch()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				b.Close()
+				return
+			case _, ok := <-w:
+				if !ok {
+					return // closed by hand.
+				}
+			}
+		}
+	}()
+	return b
+}
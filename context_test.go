@@ -0,0 +1,48 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/bchantest"
+)
+
+func TestNewWithContext(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bc := bchan.NewOfWithContext[string](ctx, 1)
+	bc.Bcast("bill")
+	if v := <-bc.Ch; v != "bill" {
+		t.Fatalf("expected bill, got %v", v)
+	}
+	bc.BcastAck()
+
+	cancel()
+	eventually(t, "cancel to close the Bchan", bc.IsClosed)
+
+	bc.Bcast("lyle") // no panic
+	if err := bc.TryBcast("lyle"); err != bchan.ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if _, err := bc.Recv(context.Background()); err != bchan.ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if err := bc.BcastAndWait("x", 1, time.Millisecond); err != bchan.ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestNewWithContextClosedByHand(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer bchantest.CheckGoroutines(t)()
+
+	// ctx outlives bc; closing bc must not
+	// leave its watcher waiting on ctx.
+	bc := bchan.NewOfWithContext[int](ctx, 1)
+	bc.Bcast(1)
+	bc.Close()
+}
@@ -0,0 +1,37 @@
+package bchan
+
+// Counters are running totals of b's events,
+// kept with atomics so reading them is cheap and
+// takes no lock; poll them into whatever telemetry
+// you use. Unlike Stats they hold no state, only
+// counts that never go down, so rates can be had
+// by differencing two readings.
+type Counters struct {
+	// Bcasts counts new values: every Set, Bcast
+	// and the like that got as far as taking a
+	// sequence number.
+	Bcasts uint64
+
+	// Fills, Drains and Acks are as in Stats.
+	Fills  uint64
+	Drains uint64
+	Acks   uint64
+
+	// Dropped counts values handed to b that
+	// were never broadcast: refused by a
+	// validator, an interceptor, a rate limit or
+	// backpressure, or replaced while pending
+	// under debounce or a coalescing rate limit.
+	Dropped uint64
+}
+
+// Counters returns b's event counters.
+func (b *Of[T]) Counters() Counters {
+	return Counters{
+		Bcasts:  b.nbcast.Load(),
+		Fills:   b.nfill.Load(),
+		Drains:  b.ndrain.Load(),
+		Acks:    b.nack.Load(),
+		Dropped: b.ndropped.Load(),
+	}
+}
@@ -0,0 +1,56 @@
+package bchan_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestCounters(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	bc.Bcast(1)
+	<-bc.Ch
+	bc.BcastAck()
+	bc.Bcast(2)
+
+	c := bc.Counters()
+	if c.Bcasts != 2 || c.Acks != 1 || c.Fills != 7 || c.Drains != 3 || c.Dropped != 0 {
+		t.Fatalf("bad counters %+v", c)
+	}
+
+	bc.SetDebounce(time.Hour)
+	bc.Bcast(3)
+	bc.Bcast(4) // replaces 3
+	bc.Set(5)   // discards 4
+	if c := bc.Counters(); c.Dropped != 2 || c.Bcasts != 3 {
+		t.Fatalf("bad counters after debounce %+v", c)
+	}
+}
+
+func TestCountersRejected(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.SetValidator(func(v int) error {
+		if v < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	})
+	bc.InterceptBcast(func(op string, v int, next func(v int) error) error {
+		if v == 13 {
+			return errors.New("unlucky")
+		}
+		return next(v)
+	})
+	bc.Bcast(-1)
+	bc.Bcast(13)
+	bc.SetRateLimit(0.001, 1, true)
+	bc.Bcast(1)
+	bc.TryBcast(2)
+	if c := bc.Counters(); c.Dropped != 3 || c.Bcasts != 1 {
+		t.Fatalf("expected the validator, interceptor and rate limit refusals to count, got %+v", c)
+	}
+}
@@ -0,0 +1,49 @@
+package bchan
+
+import (
+	"time"
+)
+
+// SlowAck reports a receiver that held a value
+// past the SetAckDeadline deadline before acking.
+type SlowAck struct {
+	// Receiver names the registered receiver
+	// (see RegisterReceiver), if it was one.
+	Receiver string
+
+	// Site is the file:line of the receive.
+	Site string
+
+	// Seq is the sequence number (see Seq)
+	// current at the receive.
+	Seq uint64
+
+	// Held is how long the ack took.
+	Held time.Duration
+}
+
+// SetAckDeadline gives each delivery a deadline d
+// for its BcastAck. A receiver that holds a value
+// longer is starving the rest of the fan-out of
+// that slot; each such ack is counted in
+// Stats.SlowAcks and, if report is not nil, passed
+// to it. Receives are seen as for CheckAcks, so
+// receivers must use RecvChecked, Recv, TryRecv or
+// ToChan, and report likewise runs with b locked.
+// A receive that is never acked is not reported
+// here; CheckAcks catches those. d <= 0 turns
+// deadlines off.
+func (b *Of[T]) SetAckDeadline(d time.Duration, report func(SlowAck)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.tracking()
+	if d <= 0 {
+		c.deadline, c.slow = 0, nil
+	} else {
+		if report == nil {
+			report = func(SlowAck) {}
+		}
+		c.deadline, c.slow = d, report
+	}
+	b.retrack()
+}
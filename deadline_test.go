@@ -0,0 +1,58 @@
+package bchan_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSetAckDeadline(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	var got []bchan.SlowAck
+	bc.SetAckDeadline(10*time.Millisecond, func(s bchan.SlowAck) {
+		got = append(got, s)
+	})
+	bc.Bcast(1)
+	ctx := context.Background()
+
+	// prompt
+	if _, err := bc.RecvChecked(ctx); err != nil {
+		t.Fatal(err)
+	}
+	bc.BcastAck()
+
+	// slow
+	if _, err := bc.RecvChecked(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	bc.BcastAck()
+
+	if len(got) != 1 {
+		t.Fatalf("got %d slow acks, want 1: %v", len(got), got)
+	}
+	if got[0].Held < 10*time.Millisecond || got[0].Seq != 1 || !strings.Contains(got[0].Site, "deadline_test.go:") {
+		t.Fatalf("bad report %+v", got[0])
+	}
+	if n := bc.Stats().SlowAcks; n != 1 {
+		t.Fatalf("Stats.SlowAcks = %d, want 1", n)
+	}
+
+	// an ack with no receive is not SetAckDeadline's business.
+	bc.BcastAck()
+	if n := bc.Stats().AckViolations; n != 0 {
+		t.Fatalf("Stats.AckViolations = %d without CheckAcks", n)
+	}
+
+	bc.SetAckDeadline(0, nil)
+	bc.RecvChecked(ctx)
+	time.Sleep(20 * time.Millisecond)
+	bc.BcastAck()
+	if len(got) != 1 {
+		t.Fatal("reported after deadlines were turned off")
+	}
+}
@@ -0,0 +1,92 @@
+package bchan
+
+import (
+	"time"
+)
+
+// SetDebounce turns on coalescing of bursty updates:
+// the first Bcast in a burst starts a window of
+// length d, further Bcast calls inside the window
+// just replace the pending value, and when the window
+// closes the last value is broadcast once. Receivers
+// are thereby woken at most once per window however
+// fast the producer goes. d <= 0 turns debouncing
+// off, broadcasting any pending value at once.
+//
+// Set, Clear, Close, and the other broadcast
+// methods are not debounced, and discard any
+// pending value.
+func (b *Of[T]) SetDebounce(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.debounce = d
+	if d <= 0 && b.hasPending {
+		val := b.pending
+		b.staged = b.pendingMeta
+		b.cancelPending()
+		b.store("Bcast", val)
+		b.activate()
+	}
+}
+
+// coalesce holds val as the pending value, to be
+// broadcast after wait unless a flush is already due.
+// Caller must hold b.mu.
+func (b *Of[T]) coalesce(val T, wait time.Duration) {
+	if b.hasPending {
+		b.ndropped.Add(1)
+	}
+	b.pending = val
+	b.pendingMeta = b.staged
+	b.staged = Meta{}
+	b.hasPending = true
+	if b.flushTimer == nil {
+		b.scheduleFlush(wait)
+	}
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) scheduleFlush(wait time.Duration) {
+	b.flushGen++
+	gen := b.flushGen
+	b.flushTimer = b.afterFunc(wait, func() { b.flushPending(gen) })
+}
+
+// flushPending runs when a debounce window closes,
+// or when a rate-limited value's turn comes.
+// gen guards against a timer that fired just as
+// it was being cancelled.
+func (b *Of[T]) flushPending(gen uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.flushGen || !b.hasPending || b.closed {
+		return
+	}
+	b.flushTimer = nil
+	if b.limit != nil && !b.limit.take(b.now()) {
+		b.scheduleFlush(b.limit.wait(b.now()))
+		return
+	}
+	val := b.pending
+	b.staged = b.pendingMeta
+	b.hasPending = false // sent, not dropped
+	b.cancelPending()
+	b.store("Bcast", val)
+	b.activate()
+}
+
+// cancelPending discards any debounced value.
+// Caller must hold b.mu.
+func (b *Of[T]) cancelPending() {
+	if b.hasPending {
+		b.ndropped.Add(1)
+	}
+	if b.flushTimer != nil {
+		b.flushTimer.Stop()
+		b.flushTimer = nil
+	}
+	var zero T
+	b.pending = zero
+	b.pendingMeta = Meta{}
+	b.hasPending = false
+}
@@ -0,0 +1,40 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestDebounce(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[int](bchan.WithDebounce(30 * time.Millisecond))
+	for i := 1; i <= 100; i++ {
+		bc.Bcast(i)
+	}
+	if bc.Seq() != 0 || len(bc.Ch) != 0 {
+		t.Fatal("burst should still be pending inside the window")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if v, ok := bc.Cur(); !ok || v != 100 {
+		t.Fatalf("expected the final value 100 to be on, got %v, %v", v, ok)
+	}
+	if bc.Seq() != 1 {
+		t.Fatalf("expected one coalesced delivery, got seq %v", bc.Seq())
+	}
+
+	bc.Bcast(101)
+	bc.Clear() // discards the pending 101
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := bc.Cur(); ok {
+		t.Fatal("Clear should cancel a pending debounced value")
+	}
+
+	bc.Bcast(102)
+	bc.SetDebounce(0) // flushes at once
+	if v := <-bc.Ch; v != 102 {
+		t.Fatalf("expected 102, got %v", v)
+	}
+}
@@ -0,0 +1,98 @@
+package bchan
+
+import (
+	"sync"
+)
+
+// Delta is one change broadcast by a Deltas,
+// numbered so that gaps can be spotted: Seq goes
+// up by exactly one per Update.
+type Delta[D any] struct {
+	Seq    uint64
+	Change D
+}
+
+// Missed is how many deltas came between the one
+// numbered last and d, unseen: 0 if d follows on.
+// A receiver that missed any must start again from
+// Snapshot.
+func (d Delta[D]) Missed(last uint64) uint64 {
+	if d.Seq <= last+1 {
+		return 0
+	}
+	return d.Seq - last - 1
+}
+
+// Deltas broadcasts changes to a large value instead
+// of the value itself. The producer hands each new
+// full state to Update, which diffs it against the
+// last and broadcasts just the difference; receivers
+// start from Snapshot and apply the deltas that
+// follow, taking them from Subscribe. The Bchan
+// itself keeps only the latest delta, so a receiver
+// there that falls behind misses some; a subscription
+// queues them. Either way, Delta.Missed tells when to
+// start again from Snapshot.
+type Deltas[T, D any] struct {
+	b *Of[Delta[D]]
+
+	mu   sync.Mutex
+	full T
+	seq  uint64
+	diff func(old, new T) D
+}
+
+// NewDeltas makes a Deltas whose deltas are
+// computed by diff and broadcast on a Bchan of the
+// given diameter (see New). The initial state is
+// T's zero value.
+func NewDeltas[T, D any](diameter int, diff func(old, new T) D) *Deltas[T, D] {
+	return &Deltas[T, D]{b: NewOf[Delta[D]](diameter), diff: diff}
+}
+
+// Bchan is the Bchan the deltas are broadcast on.
+// Receive from it, and ack, as from any other, but
+// note that it skips deltas a slow receiver has not
+// yet taken; Subscribe does not.
+func (d *Deltas[T, D]) Bchan() *Of[Delta[D]] {
+	return d.b
+}
+
+// Subscribe returns a subscription delivering every
+// delta broadcast from now on, once each and in
+// order, queueing up to n of them (see WithQueue).
+// Subscribe before taking the Snapshot, then skip
+// deltas with a Seq not past the snapshot's. Only a
+// receiver more than n behind loses deltas, which
+// the subscription's Dropped counts and Missed
+// reports.
+func (d *Deltas[T, D]) Subscribe(n int) *SubscriptionOf[Delta[D]] {
+	return d.b.Subscribe(WithQueue(n), WithoutCurrent())
+}
+
+// Update makes v the full state, broadcasting
+// the Delta from the previous one.
+func (d *Deltas[T, D]) Update(v T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	change := d.diff(d.full, v)
+	d.full = v
+	d.seq++
+	d.b.Bcast(Delta[D]{Seq: d.seq, Change: change})
+}
+
+// Snapshot returns the current full state, and the
+// Seq of the last Delta already folded into it, so a
+// late joiner can load it and then apply only the
+// deltas with a greater Seq. The state is shared with
+// the producer and must not be modified.
+func (d *Deltas[T, D]) Snapshot() (full T, seq uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.full, d.seq
+}
+
+// Close closes the deltas' Bchan.
+func (d *Deltas[T, D]) Close() {
+	d.b.Close()
+}
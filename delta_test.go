@@ -0,0 +1,82 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestDeltas(t *testing.T) {
+
+	// each delta is the keys added or changed.
+	diff := func(old, new map[string]int) map[string]int {
+		d := make(map[string]int)
+		for k, v := range new {
+			if ov, ok := old[k]; !ok || ov != v {
+				d[k] = v
+			}
+		}
+		return d
+	}
+	ds := bchan.NewDeltas(1, diff)
+	defer ds.Close()
+	ds.Update(map[string]int{"a": 1, "b": 2})
+
+	// a late joiner loads the snapshot...
+	snap, seq := ds.Snapshot()
+	state := make(map[string]int)
+	for k, v := range snap {
+		state[k] = v
+	}
+
+	// ...then applies deltas past it.
+	ds.Update(map[string]int{"a": 1, "b": 3})
+	b := ds.Bchan()
+	d := <-b.Ch
+	b.BcastAck()
+	if d.Seq != seq+1 {
+		t.Fatalf("delta seq %d does not follow snapshot seq %d", d.Seq, seq)
+	}
+	if len(d.Change) != 1 || d.Change["b"] != 3 {
+		t.Fatalf("got delta %v, want just b=3", d.Change)
+	}
+	for k, v := range d.Change {
+		state[k] = v
+	}
+	if state["a"] != 1 || state["b"] != 3 {
+		t.Fatalf("rebuilt %v", state)
+	}
+}
+
+func TestDeltasSubscribe(t *testing.T) {
+
+	ds := bchan.NewDeltas(1, func(old, new int) int { return new - old })
+	defer ds.Close()
+	ds.Update(1)
+	sub := ds.Subscribe(2)
+	_, seq := ds.Snapshot()
+
+	// a slow receiver misses nothing on the
+	// subscription, but does on the Bchan.
+	ds.Update(3)
+	ds.Update(6)
+	if d := <-ds.Bchan().Ch; d.Missed(seq) != 1 {
+		t.Fatalf("on the Bchan, want one missed before %+v", d)
+	}
+	ds.Bchan().BcastAck()
+	for _, want := range []int{2, 3} {
+		d := <-sub.Ch
+		if d.Missed(seq) != 0 || d.Change != want {
+			t.Fatalf("got %+v after seq %d, want change %d", d, seq, want)
+		}
+		seq = d.Seq
+	}
+
+	// past the queue, the gap shows.
+	for v := 10; v <= 40; v += 10 {
+		ds.Update(v)
+	}
+	if d := <-sub.Ch; d.Missed(seq) != 2 || sub.Dropped() != 2 {
+		t.Fatalf("got %+v after seq %d, dropped %d; want two missed", d, seq, sub.Dropped())
+	}
+}
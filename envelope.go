@@ -0,0 +1,143 @@
+package bchan
+
+import (
+	"time"
+)
+
+// Envelope is a broadcast value together with
+// its bookkeeping. See EnvelopeOf.
+type Envelope = EnvelopeOf[interface{}]
+
+// EnvelopeOf pairs a value with the sequence
+// number it was given when it was Set or Bcast.
+// Sequence numbers start at 1 and go up by one
+// on every Set or Bcast, so a receiver that
+// remembers the last Seq it handled can tell
+// a repeat (same Seq) from a new value, and
+// can count how many it missed in between.
+//
+// At is when the value was set. It carries a
+// monotonic clock reading as well as the wall
+// clock, so Age and Stale are immune to clock
+// steps within one process. Age and Stale go by
+// the clock of the Bchan the envelope came from
+// (see SetClock), or the system clock for one
+// made by hand. Fresh is when it was
+// last (re)broadcast; it equals At unless a
+// heartbeat is on (see SetHeartbeat).
+//
+// Meta is whatever the producer attached with
+// BcastMeta.
+type EnvelopeOf[T any] struct {
+	Val   T
+	Seq   uint64
+	At    time.Time
+	Fresh time.Time
+	Meta  Meta
+
+	// clock is the Bchan's, for Age.
+	clock Clock
+}
+
+// Meta is descriptive metadata a producer can
+// attach to a broadcast, so that in setups with
+// several producers receivers can tell where a
+// value came from.
+type Meta struct {
+	// Source identifies the producer.
+	Source string
+
+	// Version is the producer's own version
+	// string for the value, if it has one.
+	Version string
+
+	// Labels are free-form annotations.
+	Labels map[string]string
+}
+
+// BcastMeta is Bcast(val) with m attached; it is
+// reported by Envelope until the next Set or
+// Bcast, which attach none unless they are
+// BcastMeta too.
+func (b *Of[T]) BcastMeta(val T, m Meta) {
+	b.lockForBcast()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("BcastMeta") {
+		return
+	}
+	b.staged = m
+	b.bcast("BcastMeta", val)
+	b.staged = Meta{}
+}
+
+// Age is how long ago e was set.
+func (e EnvelopeOf[T]) Age() time.Duration {
+	if e.clock == nil {
+		return time.Since(e.At)
+	}
+	return e.clock.Now().Sub(e.At)
+}
+
+// Stale reports whether e is older than maxAge.
+func (e EnvelopeOf[T]) Stale(maxAge time.Duration) bool {
+	return e.Age() > maxAge
+}
+
+// NewEnveloped makes a Bchan whose values are
+// envelopes, each stamped with its Seq and At by
+// the Bchan itself as it is Set or Bcast, so the
+// producer only fills in Val (see BcastVal), and
+// every receive from Ch, a group, or a subscription
+// yields the stamped envelope.
+func NewEnveloped[T any](expectedDiameter int) *Of[EnvelopeOf[T]] {
+	b := NewOf[EnvelopeOf[T]](expectedDiameter)
+	b.stamp = func(e EnvelopeOf[T], seq uint64, at time.Time, m Meta, clock Clock) EnvelopeOf[T] {
+		e.Seq = seq
+		e.At = at
+		e.Fresh = at
+		e.clock = clock
+		if m.Source != "" || m.Version != "" || m.Labels != nil {
+			e.Meta = m
+		}
+		return e
+	}
+	b.restamp = func(e EnvelopeOf[T], fresh time.Time) EnvelopeOf[T] {
+		e.Fresh = fresh
+		return e
+	}
+	return b
+}
+
+// BcastVal broadcasts val in a fresh envelope on
+// a Bchan made with NewEnveloped.
+func BcastVal[T any](b *Of[EnvelopeOf[T]], val T) {
+	b.Bcast(EnvelopeOf[T]{Val: val})
+}
+
+// BcastValMeta is BcastVal with m attached to
+// the envelope.
+func BcastValMeta[T any](b *Of[EnvelopeOf[T]], val T, m Meta) {
+	b.BcastMeta(EnvelopeOf[T]{Val: val}, m)
+}
+
+// Seq returns the sequence number of the
+// current value; 0 means nothing has been
+// Set or Bcast yet.
+func (b *Of[T]) Seq() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.seq
+}
+
+// Envelope returns the current value and
+// its sequence number as one consistent read.
+func (b *Of[T]) Envelope() EnvelopeOf[T] {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.envelope()
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) envelope() EnvelopeOf[T] {
+	return EnvelopeOf[T]{Val: b.cur, Seq: b.seq, At: b.setAt, Fresh: b.freshAt, Meta: b.meta, clock: b.clock}
+}
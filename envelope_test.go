@@ -0,0 +1,72 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSeq(t *testing.T) {
+
+	bc := bchan.NewOf[string](1)
+	if bc.Seq() != 0 {
+		t.Fatal("fresh Bchan should have Seq 0")
+	}
+	bc.Set("a")
+	bc.Bcast("b")
+	bc.Bcast("b")
+	env := bc.Envelope()
+	if env.Seq != 3 || env.Val != "b" {
+		t.Fatalf("expected {b 3}, got %+v", env)
+	}
+	bc.On()
+	bc.Clear()
+	if bc.Seq() != 3 {
+		t.Fatal("only Set and Bcast advance Seq")
+	}
+}
+
+func TestEnveloped(t *testing.T) {
+
+	before := time.Now()
+	bc := bchan.NewEnveloped[string](1)
+	bchan.BcastVal(bc, "bill")
+
+	env := <-bc.Ch
+	bc.BcastAck()
+	if env.Val != "bill" || env.Seq != 1 || env.At.Before(before) {
+		t.Fatalf("expected a stamped envelope, got %+v", env)
+	}
+	if env.Stale(time.Hour) {
+		t.Fatal("fresh envelope reported stale")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if !env.Stale(time.Millisecond) {
+		t.Fatal("old envelope should be stale")
+	}
+	if at := bc.Envelope().At; !at.Equal(bc.Get().At) {
+		t.Fatal("Envelope().At should be the set time")
+	}
+}
+
+func TestBcastMeta(t *testing.T) {
+
+	bc := bchan.New(1)
+	m := bchan.Meta{Source: "node-7", Version: "v2", Labels: map[string]string{"region": "eu"}}
+	bc.BcastMeta("cfg", m)
+	if env := bc.Envelope(); env.Meta.Source != "node-7" || env.Meta.Labels["region"] != "eu" {
+		t.Fatalf("expected metadata on the envelope, got %+v", env.Meta)
+	}
+	bc.Bcast("cfg2")
+	if env := bc.Envelope(); env.Meta.Source != "" {
+		t.Fatalf("plain Bcast should attach no metadata, got %+v", env.Meta)
+	}
+
+	ev := bchan.NewEnveloped[int](1)
+	bchan.BcastValMeta(ev, 42, bchan.Meta{Source: "producer-a"})
+	if env := <-ev.Ch; env.Val != 42 || env.Meta.Source != "producer-a" || env.Seq != 1 {
+		t.Fatalf("receivers should get value and metadata together, got %+v", env)
+	}
+}
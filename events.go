@@ -0,0 +1,63 @@
+package bchan
+
+import (
+	"time"
+)
+
+// Event describes one state transition of a Bchan,
+// as reported to an event hook (see SetEventHook).
+type Event struct {
+	// Op names the transition: On, Off, Clear,
+	// Close, Resize, Shrink, Pause, Resume, or
+	// the call that stored a new value (Set,
+	// Bcast, ...).
+	Op string
+
+	// Seq is the sequence number (see Seq) of the
+	// current value once the transition is made.
+	Seq uint64
+
+	// Val is the current value, passed through the
+	// hook's redact func if it has one.
+	Val interface{}
+
+	At time.Time
+}
+
+// SetLogger swaps in l as b's Logger (see WithLogger);
+// nil stops logging.
+func (b *Of[T]) SetLogger(l Logger) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logger = l
+}
+
+// SetEventHook has hook called on every state
+// transition of b, so its behavior can be traced
+// in place. If redact is not nil, each value is
+// passed through it before being put in the Event;
+// return a digest, or nil, to keep sensitive values
+// out of traces. hook runs with b locked, so it must
+// be quick and must not call back into b. A nil hook
+// removes the present one.
+func (b *Of[T]) SetEventHook(hook func(e Event), redact func(v T) interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hook = hook
+	b.redact = redact
+}
+
+// emit reports op to the event hook, if any.
+// Caller must hold b.mu.
+func (b *Of[T]) emit(op string) {
+	if b.hook == nil {
+		return
+	}
+	e := Event{Op: op, Seq: b.seq, At: b.now()}
+	if b.redact != nil {
+		e.Val = b.redact(b.cur)
+	} else {
+		e.Val = b.cur
+	}
+	b.hook(e)
+}
@@ -0,0 +1,84 @@
+package bchan_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestEventHook(t *testing.T) {
+
+	bc := bchan.NewOf[string](1)
+	var evs []bchan.Event
+	bc.SetEventHook(func(e bchan.Event) {
+		evs = append(evs, e)
+	}, func(v string) interface{} {
+		return len(v)
+	})
+
+	bc.Set("secret")
+	bc.On()
+	bc.Off()
+	bc.Bcast("hunter2")
+	bc.Resize(3)
+	bc.Pause()
+	bc.Resume()
+	bc.Clear()
+	bc.Close()
+
+	var ops []string
+	for _, e := range evs {
+		ops = append(ops, e.Op)
+		if e.At.IsZero() {
+			t.Fatalf("%s event has no time", e.Op)
+		}
+	}
+	got := strings.Join(ops, " ")
+	want := "Set On Off Bcast Resize Pause Resume Clear Close"
+	if got != want {
+		t.Fatalf("got events %q, want %q", got, want)
+	}
+	if evs[0].Val != 6 || evs[0].Seq != 1 {
+		t.Fatalf("Set event should carry the redacted value: %+v", evs[0])
+	}
+	if evs[3].Val != 7 || evs[3].Seq != 2 {
+		t.Fatalf("Bcast event should carry the redacted value: %+v", evs[3])
+	}
+}
+
+func TestEventHookUnredacted(t *testing.T) {
+
+	bc := bchan.New(1)
+	var last bchan.Event
+	bc.SetEventHook(func(e bchan.Event) { last = e }, nil)
+	bc.Bcast("bill")
+	if last.Op != "Bcast" || last.Val != "bill" {
+		t.Fatalf("unexpected event %+v", last)
+	}
+
+	bc.SetEventHook(nil, nil)
+	bc.Off()
+	if last.Op != "Bcast" {
+		t.Fatal("removed hook still called")
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+
+	bc := bchan.New(1)
+	var buf bytes.Buffer
+	bc.SetLogger(log.New(&buf, "", 0))
+	bc.Bcast("bill")
+	if !strings.Contains(buf.String(), "bchan: Bcast seq=1") {
+		t.Fatalf("expected Bcast logged, got %q", buf.String())
+	}
+	bc.SetLogger(nil)
+	buf.Reset()
+	bc.Off()
+	if buf.Len() != 0 {
+		t.Fatalf("logged after SetLogger(nil): %q", buf.String())
+	}
+}
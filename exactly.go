@@ -0,0 +1,203 @@
+package bchan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ExactlyOnce makes a registered receiver one that
+// is handed every distinct value broadcast after it
+// registers, each exactly once and in order: none
+// is skipped because a newer one replaced it, and
+// none repeats because it is still current. Such a
+// receiver does not read Ch; its values wait for it
+// in a queue of its own, which grows without limit
+// if it falls behind. Its deliveries are counted per
+// value for Deliveries, an account that grows by
+// one entry per broadcast, so this is meant for
+// tests, audits, and modest rates.
+func ExactlyOnce() ReceiverOption {
+	return func(c *receiverConfig) {
+		c.exact = true
+	}
+}
+
+type exactState[T any] struct {
+	queue []queued[T]
+	wake  chan struct{}
+
+	// last is the seq last queued. from is the seq
+	// current at registration; those up to it were
+	// never due.
+	last uint64
+	from uint64
+
+	// got says how many times each value has
+	// been received, as Recv handed it out.
+	got map[uint64]int
+}
+
+func newExactState[T any](seq uint64) *exactState[T] {
+	return &exactState[T]{
+		wake: make(chan struct{}, 1),
+		last: seq,
+		from: seq,
+		got:  make(map[uint64]int),
+	}
+}
+
+func (e *exactState[T]) signal() {
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// feedExact logs the current value, if it is being
+// broadcast and is new, and queues it for each
+// ExactlyOnce receiver it is new to.
+// Caller must hold b.mu.
+func (b *Of[T]) feedExact(on bool) {
+	if !on {
+		return
+	}
+	logged := false
+	for _, r := range b.receivers {
+		e := r.exact
+		if e == nil {
+			continue
+		}
+		if n := len(b.exactLog); !logged && (n == 0 || b.exactLog[n-1] < b.seq) {
+			b.exactLog = append(b.exactLog, b.seq)
+		}
+		logged = true
+		if b.seq <= e.last {
+			continue
+		}
+		e.queue = append(e.queue, queued[T]{val: b.handout(b.cur), seq: b.seq})
+		e.last = b.seq
+		e.signal()
+	}
+}
+
+// wakeExact wakes every ExactlyOnce receiver,
+// so those waiting see b closed.
+// Caller must hold b.mu.
+func (b *Of[T]) wakeExact() {
+	for _, r := range b.receivers {
+		if r.exact != nil {
+			r.exact.signal()
+		}
+	}
+}
+
+// recvExact is Recv for an ExactlyOnce receiver.
+// Values queued before b was closed are still
+// handed out; then it returns ErrClosed.
+func (r *ReceiverOf[T]) recvExact(ctx context.Context) (val T, err error) {
+	b, e := r.b, r.exact
+	for {
+		b.mu.Lock()
+		if len(e.queue) > 0 {
+			it := e.queue[0]
+			e.queue[0] = queued[T]{}
+			e.queue = e.queue[1:]
+			e.got[it.seq]++
+			r.nrecv++
+			r.lastSeq = it.seq
+			r.holding = true
+			r.since = b.now()
+			b.mu.Unlock()
+			return it.val, nil
+		}
+		closed := b.closed
+		b.mu.Unlock()
+		if closed {
+			return val, ErrClosed
+		}
+		select {
+		case <-e.wake:
+		case <-ctx.Done():
+			return val, ctx.Err()
+		}
+	}
+}
+
+// DeliveryMatrix accounts for the deliveries
+// made to ExactlyOnce receivers.
+type DeliveryMatrix struct {
+	// Receivers names the ExactlyOnce
+	// receivers, in order.
+	Receivers []string
+
+	// Seqs lists, in order, the sequence numbers
+	// of every value broadcast since the first of
+	// them registered, and of any other value one
+	// of them received.
+	Seqs []uint64
+
+	// Counts[i][j] is how many times Receivers[i]
+	// has received the value numbered Seqs[j], or
+	// -1 if that value was never due to it: it
+	// registered later.
+	Counts [][]int
+}
+
+// Deliveries returns the delivery matrix for b's
+// ExactlyOnce receivers.
+func (b *Of[T]) Deliveries() DeliveryMatrix {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var m DeliveryMatrix
+	seqs := make(map[uint64]bool)
+	var exact []*ReceiverOf[T]
+	for _, r := range b.receivers {
+		if r.exact == nil {
+			continue
+		}
+		exact = append(exact, r)
+		for _, seq := range b.exactLog {
+			if seq > r.exact.from {
+				seqs[seq] = true
+			}
+		}
+		for seq := range r.exact.got {
+			seqs[seq] = true
+		}
+	}
+	sort.Slice(exact, func(i, j int) bool { return exact[i].Name < exact[j].Name })
+	for seq := range seqs {
+		m.Seqs = append(m.Seqs, seq)
+	}
+	sort.Slice(m.Seqs, func(i, j int) bool { return m.Seqs[i] < m.Seqs[j] })
+	for _, r := range exact {
+		m.Receivers = append(m.Receivers, r.Name)
+		row := make([]int, len(m.Seqs))
+		for j, seq := range m.Seqs {
+			row[j] = r.exact.got[seq]
+			if seq <= r.exact.from && row[j] == 0 {
+				row[j] = -1
+			}
+		}
+		m.Counts = append(m.Counts, row)
+	}
+	return m
+}
+
+// Check returns an error naming every value that
+// was due to a receiver and not received exactly
+// once. Values still queued count as not received,
+// so check once the receivers have caught up.
+func (m DeliveryMatrix) Check() error {
+	var errs []error
+	for i, row := range m.Counts {
+		for j, n := range row {
+			if n != -1 && n != 1 {
+				errs = append(errs, fmt.Errorf("bchan: receiver %q got seq %d %d times", m.Receivers[i], m.Seqs[j], n))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
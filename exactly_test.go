@@ -0,0 +1,65 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestExactlyOnce(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(0) // current at registration: not due
+	a := bc.RegisterReceiver("a", bchan.ExactlyOnce())
+	for v := 1; v <= 3; v++ {
+		bc.Bcast(v)
+	}
+	bc.Off()
+	bc.On() // same value again: not a new broadcast
+	late := bc.RegisterReceiver("late", bchan.ExactlyOnce())
+	bc.Bcast(4)
+
+	ctx := context.Background()
+	for want := 1; want <= 4; want++ {
+		v, err := a.Recv(ctx)
+		if err != nil || v != want {
+			t.Fatalf("a got %d, %v; want %d, nil", v, err, want)
+		}
+		a.Ack()
+	}
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := a.Recv(short); err != context.DeadlineExceeded {
+		t.Fatalf("a should have nothing more; got %v", err)
+	}
+
+	m := bc.Deliveries()
+	if err := m.Check(); err == nil {
+		t.Fatal("late has not received seq 5 yet; Check should say so")
+	}
+	if v, err := late.Recv(ctx); err != nil || v != 4 {
+		t.Fatalf("late got %d, %v; want 4, nil", v, err)
+	}
+
+	m = bc.Deliveries()
+	if err := m.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Receivers) != 2 || len(m.Seqs) != 4 {
+		t.Fatalf("bad matrix %+v", m)
+	}
+	if m.Counts[1][0] != -1 || m.Counts[1][3] != 1 {
+		t.Fatalf("late's row is %v; want -1s then 1", m.Counts[1])
+	}
+
+	bc.Bcast(5)
+	bc.Close()
+	if v, err := late.Recv(ctx); err != nil || v != 5 {
+		t.Fatalf("a value queued before Close should still be delivered; got %d, %v", v, err)
+	}
+	if _, err := late.Recv(ctx); err != bchan.ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
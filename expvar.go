@@ -0,0 +1,15 @@
+package bchan
+
+import (
+	"expvar"
+)
+
+// ExposeExpvar publishes b's Stats under name
+// in the expvar registry, so they show up in
+// /debug/vars. Like expvar.Publish, it panics
+// if name is already in use.
+func (b *Of[T]) ExposeExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return b.Stats()
+	}))
+}
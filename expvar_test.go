@@ -0,0 +1,28 @@
+package bchan_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestExposeExpvar(t *testing.T) {
+
+	bc := bchan.New(1)
+	bc.ExposeExpvar("bchan_test_expvar")
+	bc.Bcast("bill")
+
+	v := expvar.Get("bchan_test_expvar")
+	if v == nil {
+		t.Fatal("expected var to be published")
+	}
+	var st bchan.Stats
+	if err := json.Unmarshal([]byte(v.String()), &st); err != nil {
+		t.Fatal(err)
+	}
+	if !st.On || st.Seq != 1 || st.Fills != 2 {
+		t.Fatalf("unexpected published stats %+v", st)
+	}
+}
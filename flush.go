@@ -0,0 +1,58 @@
+package bchan
+
+import (
+	"context"
+)
+
+// Flush empties Ch and every consumer group's
+// channel and, if broadcasting is on, refills them
+// at once with fresh copies of the current value,
+// as a new Bcast of it would but without a new
+// sequence number. In at-most-once mode (see
+// WithAtMostOnce) nothing is ever handed out twice,
+// so Flush leaves the channels be.
+func (b *Of[T]) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("Flush") || b.onceFilled != nil {
+		return
+	}
+	b.drain()
+	if b.on {
+		b.fill()
+	}
+}
+
+// WaitEmpty blocks until every copy of a value
+// sitting in Ch when it was called has been taken
+// out, whether received or drained by Off, Clear,
+// a new Bcast and so on, for orderly shutdown and
+// for tests that need to reach a quiet state.
+// Copies put in later, such as by the refill after
+// each ack, are not waited for. Receives are noticed
+// when receivers ack. It returns ctx.Err() if ctx is
+// done first, and nil once b is closed.
+func (b *Of[T]) WaitEmpty(ctx context.Context) error {
+	b.mu.Lock()
+	target := b.chFills
+	b.mu.Unlock()
+	for {
+		b.mu.Lock()
+		// arm the wakeup before counting; see ack.
+		if b.ackWait == nil {
+			b.ackWait = make(chan struct{})
+			b.ackWaiting.Store(true)
+		}
+		wake := b.ackWait
+		done := b.closed || b.chFills-uint64(len(b.Ch)) >= target
+		b.mu.Unlock()
+		if done {
+			return nil
+		}
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
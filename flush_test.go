@@ -0,0 +1,63 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestFlush(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	bc.Bcast(1)
+	<-bc.Ch // taken, and not acked
+	if n := len(bc.Ch); n != 2 {
+		t.Fatalf("len %d, want 2", n)
+	}
+	bc.Flush()
+	if n := len(bc.Ch); n != 3 {
+		t.Fatalf("Flush should refill Ch; len %d, want 3", n)
+	}
+	if s := bc.Seq(); s != 1 {
+		t.Fatalf("Flush changed Seq to %d", s)
+	}
+	bc.Off()
+	bc.Flush()
+	if n := len(bc.Ch); n != 0 {
+		t.Fatalf("Flush while off should leave Ch empty; len %d", n)
+	}
+}
+
+func TestWaitEmpty(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	bc.Bcast(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bc.WaitEmpty(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("nobody receiving; expected DeadlineExceeded, got %v", err)
+	}
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(2 * time.Millisecond)
+			<-bc.Ch
+			bc.BcastAck()
+		}
+	}()
+	if err := bc.WaitEmpty(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// draining counts as taking.
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		bc.Off()
+	}()
+	if err := bc.WaitEmpty(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,87 @@
+package bchan
+
+import (
+	"context"
+)
+
+// Follow calls fn with b's current value, its
+// sequence number (see Seq), and whether it is
+// being broadcast, then again after every change
+// to any of them, until ctx is done, b is closed,
+// or fn returns an error; it returns ctx.Err(),
+// ErrClosed, or fn's error respectively. Like a
+// linked child (see AddChild), Follow takes no part
+// in the ack protocol and under a burst of updates
+// may skip straight to the latest value. It is the
+// building block for relaying a Bchan elsewhere,
+// such as over the network. fn is given each value
+// as receivers of Ch are, so copied and intercepted
+// as SetClone and InterceptDeliver ask. fn runs on
+// the caller's goroutine, without b locked.
+func (b *Of[T]) Follow(ctx context.Context, fn func(val T, seq uint64, on bool) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	wake, cancel := b.watch()
+	defer cancel()
+	var last followState[T]
+	first := true
+	for {
+		cur := b.followState()
+		on := cur.on && cur.live
+		if first || cur.seq != last.seq || on != (last.on && last.live) {
+			if err := fn(cur.handout(), cur.seq, on); err != nil {
+				return err
+			}
+		}
+		first = false
+		last = cur
+		select {
+		case _, ok := <-wake:
+			if !ok {
+				return ErrClosed
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ApplyState makes b match a state reported by
+// Follow, as a relay from another Bchan does: if
+// changed, val is broadcast as by Bcast if on, or
+// Set if not; otherwise b is just turned on or off.
+// Unlike those methods it does not panic if b is
+// closed but returns ErrClosed, so that a relay
+// running on its own goroutine can stop. It also
+// returns why a broadcast val was refused, if it
+// was (see Bcast's interceptors, validator, and
+// backpressure).
+func (b *Of[T]) ApplyState(val T, changed, on bool) error {
+	if changed && on {
+		b.lockForBcast()
+	} else {
+		b.mu.Lock()
+	}
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	switch {
+	case changed && on:
+		return b.bcast("ApplyState", val)
+	case changed:
+		if err := b.admit("ApplyState", &val); err != nil {
+			return err
+		}
+		b.cancelPending()
+		b.store("ApplyState", val)
+		b.drain()
+		b.publish(false)
+	case on:
+		b.turnOn()
+	default:
+		b.off()
+	}
+	return nil
+}
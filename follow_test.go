@@ -0,0 +1,70 @@
+package bchan_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestFollow(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(1)
+
+	type seen struct {
+		val int
+		on  bool
+	}
+	var mu sync.Mutex
+	var got []seen
+	last := func() seen {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(got) == 0 {
+			return seen{}
+		}
+		return got[len(got)-1]
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- bc.Follow(context.Background(), func(v int, seq uint64, on bool) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, seen{v, on})
+			return nil
+		})
+	}()
+
+	eventually(t, "the initial value", func() bool { return last() == seen{1, true} })
+	bc.Off()
+	eventually(t, "Off", func() bool { return last() == seen{1, false} })
+	bc.Bcast(2)
+	eventually(t, "the new value", func() bool { return last() == seen{2, true} })
+
+	bc.Close()
+	if err := <-errc; !errors.Is(err, bchan.ErrClosed) {
+		t.Fatalf("Follow on a closed Bchan returned %v, want ErrClosed", err)
+	}
+}
+
+func TestApplyState(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	if err := bc.ApplyState(1, true, false); err != nil || bc.IsOn() || bc.Get() != 1 {
+		t.Fatalf("changed and off should Set 1: err %v, on %v, value %d", err, bc.IsOn(), bc.Get())
+	}
+	if err := bc.ApplyState(1, false, true); err != nil || !bc.IsOn() {
+		t.Fatalf("unchanged and on should turn on: err %v", err)
+	}
+	if err := bc.ApplyState(2, true, true); err != nil || bc.Get() != 2 {
+		t.Fatalf("changed and on should Bcast 2: err %v, value %d", err, bc.Get())
+	}
+	bc.Close()
+	if err := bc.ApplyState(3, true, true); err != bchan.ErrClosed {
+		t.Fatalf("expected ErrClosed, not a panic; got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package bchan
+
+// Clone forks b: it returns a new Bchan of the same
+// diameter, with its own Ch, holding a copy of b's
+// current value (see SetClone) and broadcasting it
+// if b is. The new Bchan starts its own sequence,
+// at 1 if b had a value, and takes on b's settings
+// (logger, clock, event hook, debounce, equality, value copying,
+// validator, reducer, interceptors, codec,
+// history length, envelope stamping, and
+// at-most-once mode),
+// so a configured Bchan can serve as the template
+// for, say, one broadcast domain per tenant. Nothing
+// live is shared: not subscriptions, groups,
+// children, observers, journals, timers or history.
+func (b *Of[T]) Clone() *Of[T] {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	c := NewOf[T](cap(b.Ch) - 1)
+	if b.condBackend {
+		c.setCh(make(chan T))
+		c.condBackend = true
+	}
+	c.logger = b.logger
+	c.clock = b.clock
+	c.hook = b.hook
+	c.redact = b.redact
+	c.debounce = b.debounce
+	c.equal = b.equal
+	c.stamp = b.stamp
+	c.restamp = b.restamp
+	c.codec = b.codec
+	c.clone = b.clone
+	c.validator = b.validator
+	c.reducer = b.reducer
+	c.bcastICs = append([]BcastInterceptor[T](nil), b.bcastICs...)
+	c.deliverICs = append([]DeliverInterceptor[T](nil), b.deliverICs...)
+	c.rebuildOut()
+	if b.onceFilled != nil {
+		c.onceFilled = make(map[chan T]uint64)
+	}
+	if b.hist != nil {
+		c.hist = newRing[T](len(b.hist.buf))
+	}
+	if b.seq == 0 {
+		return c
+	}
+
+	// c is not yet shared, but its helpers
+	// expect it locked.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.staged = b.meta
+	c.store("Clone", b.cur)
+	if b.on {
+		c.activate()
+	}
+	return c
+}
@@ -0,0 +1,96 @@
+package bchan_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestClone(t *testing.T) {
+
+	tmpl := bchan.NewOf[int](3)
+	tmpl.SetValidator(func(v int) error {
+		if v < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	})
+	tmpl.Bcast(5)
+	tmpl.Bcast(6)
+
+	c := tmpl.Clone()
+	if v, on := c.Cur(); v != 6 || !on {
+		t.Fatalf("clone has %d, on=%v; want 6, on", v, on)
+	}
+	if c.Seq() != 1 {
+		t.Fatalf("clone Seq = %d, want a fresh 1", c.Seq())
+	}
+	if cap(c.Ch) != cap(tmpl.Ch) || c.Ch == tmpl.Ch {
+		t.Fatal("clone should have its own Ch of the same size")
+	}
+	if v := <-c.Ch; v != 6 {
+		t.Fatalf("clone Ch gave %d, want 6", v)
+	}
+	c.BcastAck()
+
+	if err := c.TryBcast(-1); !errors.Is(err, bchan.ErrInvalid) {
+		t.Fatalf("clone should keep the validator; TryBcast(-1) = %v", err)
+	}
+	c.Bcast(7)
+	if tmpl.Get() != 6 {
+		t.Fatal("broadcasting on the clone changed the template")
+	}
+	tmpl.Off()
+	if !c.IsOn() {
+		t.Fatal("turning off the template turned off the clone")
+	}
+
+	if c := bchan.NewOf[int](1).Clone(); c.IsOn() || c.Seq() != 0 {
+		t.Fatal("clone of an unset Bchan should be unset and off")
+	}
+}
+
+func TestCloneEnveloped(t *testing.T) {
+
+	tmpl := bchan.NewEnveloped[string](1)
+	bchan.BcastValMeta(tmpl, "a", bchan.Meta{Source: "tmpl"})
+	c := tmpl.Clone()
+	e := c.Get()
+	if e.Val != "a" || e.Seq != 1 || e.Meta.Source != "tmpl" {
+		t.Fatalf("clone envelope is %+v", e)
+	}
+	bchan.BcastVal(c, "b")
+	if e := c.Get(); e.Seq != 2 || e.Meta.Source != "" {
+		t.Fatalf("clone should stamp its own envelopes; got %+v", e)
+	}
+}
+
+func TestCloneEnvelopedOwnClock(t *testing.T) {
+
+	tmpl := bchan.NewEnveloped[int](1)
+	c := tmpl.Clone()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			tmpl.SetClock(stoppedClock{at: start.Add(time.Hour)})
+			bchan.BcastVal(tmpl, i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			c.SetClock(stoppedClock{at: start})
+			bchan.BcastVal(c, i)
+		}
+	}()
+	wg.Wait()
+	if age := c.Get().Age(); age != 0 {
+		t.Fatalf("clone's envelope aged %v by the template's clock", age)
+	}
+}
@@ -0,0 +1,42 @@
+package bchan
+
+// FromChan returns a Bchan that broadcasts each
+// value received from src. It owns the forwarding
+// goroutine: when src is closed, the Bchan is closed
+// after it, and closing the Bchan stops the
+// forwarding, leaving src to its owner. See New for
+// the meaning of diameter.
+func FromChan(src <-chan interface{}, diameter int) *Bchan {
+	return FromChanOf(src, diameter)
+}
+
+// FromChanOf is the type-parameterized FromChan.
+func FromChanOf[T any](src <-chan T, diameter int) *Of[T] {
+	b := NewOf[T](diameter)
+	w, _ := b.watch()
+	go func() {
+		for {
+			select {
+			case v, ok := <-src:
+				if !ok {
+					b.Close()
+					return
+				}
+				b.lockForBcast()
+				closed := b.closed
+				if !closed {
+					b.bcast("FromChan", v)
+				}
+				b.mu.Unlock()
+				if closed {
+					return
+				}
+			case _, ok := <-w:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return b
+}
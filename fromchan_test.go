@@ -0,0 +1,49 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestFromChan(t *testing.T) {
+
+	src := make(chan interface{})
+	bc := bchan.FromChan(src, 2)
+
+	src <- "a"
+	src <- "b"
+	deadline := time.Now().Add(5 * time.Second)
+	for bc.Get() != "b" {
+		if time.Now().After(deadline) {
+			t.Fatalf("never saw b; have %v", bc.Get())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if v := <-bc.Ch; v != "b" {
+		t.Fatalf("got %v, want b", v)
+	}
+	bc.BcastAck()
+
+	close(src)
+	for range bc.Ch {
+		bc.BcastAck()
+	}
+	if !bc.IsClosed() {
+		t.Fatal("closing src should close the Bchan")
+	}
+}
+
+func TestFromChanOfStops(t *testing.T) {
+
+	src := make(chan int)
+	bc := bchan.FromChanOf(src, 1)
+	bc.Close()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case src <- 1:
+		t.Fatal("still forwarding after Close")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,94 @@
+package bchan
+
+import (
+	"context"
+)
+
+// Future is the next value of a Bchan, yet to
+// come. See FutureOf.
+type Future = FutureOf[interface{}]
+
+// FutureOf is a one-shot handle, made by Next,
+// on the first value broadcast after it was made.
+type FutureOf[T any] struct {
+	done  chan struct{}
+	val   T
+	err   error
+	after uint64
+	stop  func() bool
+}
+
+// Next returns a Future that resolves with the
+// first value broadcast after the call: never the
+// value current at the time, even if it is still
+// being broadcast, and exactly the next one however
+// quickly others follow it. The Future fails with
+// ctx.Err() if ctx is done first, or with ErrClosed
+// if b is closed.
+func (b *Of[T]) Next(ctx context.Context) *FutureOf[T] {
+	f := &FutureOf[T]{done: make(chan struct{})}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		f.err = ErrClosed
+		close(f.done)
+		return f
+	}
+	f.after = b.seq
+	if b.futures == nil {
+		b.futures = make(map[*FutureOf[T]]struct{})
+	}
+	b.futures[f] = struct{}{}
+	f.stop = context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.futures[f]; ok {
+			delete(b.futures, f)
+			f.err = ctx.Err()
+			close(f.done)
+		}
+	})
+	return f
+}
+
+// Done is closed once f has resolved or failed.
+func (f *FutureOf[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until f resolves and returns its
+// value, or the reason it failed.
+func (f *FutureOf[T]) Wait() (T, error) {
+	<-f.done
+	return f.val, f.err
+}
+
+// resolveFutures settles the futures waiting on a
+// value newer than the current one, if it is
+// being broadcast.
+// Caller must hold b.mu.
+func (b *Of[T]) resolveFutures(on bool) {
+	if !on {
+		return
+	}
+	for f := range b.futures {
+		if b.seq <= f.after {
+			continue
+		}
+		delete(b.futures, f)
+		f.stop()
+		f.val = b.handout(b.cur)
+		close(f.done)
+	}
+}
+
+// failFutures fails every waiting future with err.
+// Caller must hold b.mu.
+func (b *Of[T]) failFutures(err error) {
+	for f := range b.futures {
+		delete(b.futures, f)
+		f.stop()
+		f.err = err
+		close(f.done)
+	}
+}
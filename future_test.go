@@ -0,0 +1,49 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestNext(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(1)
+
+	f := bc.Next(context.Background())
+	select {
+	case <-f.Done():
+		t.Fatal("Next resolved with the current value")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	bc.Bcast(2)
+	bc.Bcast(3)
+	v, err := f.Wait()
+	if err != nil || v != 2 {
+		t.Fatalf("got %v, %v; want 2, nil", v, err)
+	}
+}
+
+func TestNextFails(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	f := bc.Next(ctx)
+	cancel()
+	if _, err := f.Wait(); err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+
+	f = bc.Next(context.Background())
+	bc.Close()
+	if _, err := f.Wait(); err != bchan.ErrClosed {
+		t.Fatalf("got %v, want ErrClosed", err)
+	}
+	if _, err := bc.Next(context.Background()).Wait(); err != bchan.ErrClosed {
+		t.Fatalf("Next after Close got %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/glycerine/bchan
+
+go 1.23
@@ -0,0 +1,91 @@
+package bchan
+
+import (
+	"context"
+)
+
+// ConsumerGroup is a named, independently
+// acked channel on a Bchan. See ConsumerGroupOf.
+type ConsumerGroup = ConsumerGroupOf[interface{}]
+
+// ConsumerGroupOf is a second (third, ...)
+// buffered channel carrying the same broadcast
+// as Bchan.Ch, but sized and refilled on its own.
+// Receivers in a group follow the usual rule,
+// acking with the group's BcastAck rather than
+// the Bchan's, so a slow or large group does not
+// eat into the diameter of any other.
+type ConsumerGroupOf[T any] struct {
+	Ch   chan T
+	Name string
+	b    *Of[T]
+}
+
+// Group returns the consumer group called name,
+// creating it with the same expected diameter
+// as b if it does not exist yet.
+func (b *Of[T]) Group(name string) *ConsumerGroupOf[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if g, ok := b.groups[name]; ok {
+		return g
+	}
+	return b.newGroup(name, cap(b.Ch)-1)
+}
+
+// NewGroup is like Group but lets you give the
+// group its own expectedDiameter; see New.
+// If the group already exists, it is returned
+// unchanged.
+func (b *Of[T]) NewGroup(name string, expectedDiameter int) *ConsumerGroupOf[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if g, ok := b.groups[name]; ok {
+		return g
+	}
+	return b.newGroup(name, expectedDiameter)
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) newGroup(name string, expectedDiameter int) *ConsumerGroupOf[T] {
+	if expectedDiameter <= 0 {
+		expectedDiameter = 1
+	}
+	g := &ConsumerGroupOf[T]{
+		Ch:   make(chan T, expectedDiameter+1),
+		Name: name,
+		b:    b,
+	}
+	if !b.isOpenFor("Group") {
+		close(g.Ch)
+		return g
+	}
+	if b.groups == nil {
+		b.groups = make(map[string]*ConsumerGroupOf[T])
+	}
+	b.groups[name] = g
+	if b.on && b.live {
+		b.fillCh(g.Ch)
+	}
+	return g
+}
+
+// BcastAck must be called after every receive
+// on g.Ch. It refills only g.Ch.
+func (g *ConsumerGroupOf[T]) BcastAck() {
+	g.b.ack(g.Ch)
+}
+
+// Recv is the group version of Bchan.Recv.
+func (g *ConsumerGroupOf[T]) Recv(ctx context.Context) (val T, err error) {
+	select {
+	case v, ok := <-g.Ch:
+		if !ok {
+			return val, ErrClosed
+		}
+		g.BcastAck()
+		return v, nil
+	case <-ctx.Done():
+		return val, ctx.Err()
+	}
+}
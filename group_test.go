@@ -0,0 +1,52 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestConsumerGroup(t *testing.T) {
+
+	bc := bchan.New(1)
+	workers := bc.NewGroup("workers", 4)
+	if bc.Group("workers") != workers {
+		t.Fatal("Group should return the existing group")
+	}
+
+	bc.Bcast("bill")
+
+	// a population of non-acking receivers in
+	// the main channel does not starve workers.
+	for len(bc.Ch) > 0 {
+		<-bc.Ch
+	}
+	for i := 0; i < 10; i++ {
+		select {
+		case v := <-workers.Ch:
+			workers.BcastAck()
+			if v != "bill" {
+				t.Fatalf("expected bill, got %v", v)
+			}
+		default:
+			t.Fatal("workers group should be refilled by its own acks")
+		}
+	}
+	if cap(workers.Ch) != 5 {
+		t.Fatalf("expected workers group sized from its own diameter, cap=%v", cap(workers.Ch))
+	}
+
+	// late-created groups see the current value.
+	late := bc.Group("late")
+	if v := <-late.Ch; v != "bill" {
+		t.Fatalf("expected bill, got %v", v)
+	}
+
+	bc.Bcast("lyle")
+	if v := <-workers.Ch; v != "lyle" {
+		t.Fatalf("Bcast should drain and refill every group, got %v", v)
+	}
+	bc.Close()
+	for range workers.Ch {
+	}
+}
@@ -0,0 +1,132 @@
+// package grpcbridge carries a Bchan across process
+// boundaries over gRPC.
+//
+// A Server streams a local Bchan to every client that
+// calls its Watch method; Mirror, on the client side,
+// copies that stream into a local Bchan, so consumers
+// there use Ch and BcastAck exactly as they would
+// in-process. Values travel as JSON, so the service
+// needs no generated protobuf code; use a concrete T
+// with bchan.Of[T] rather than a plain Bchan, as JSON
+// does not preserve the dynamic type of interface{}
+// values.
+package grpcbridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/glycerine/bchan"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// ServiceName is the gRPC service a Server registers.
+const ServiceName = "bchan.Broadcast"
+
+const watchMethod = "/" + ServiceName + "/Watch"
+
+// codecName is the content-subtype the bridge
+// is spoken in.
+const codecName = "bchanjson"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return codecName }
+
+// frame is one message on the Watch stream: the
+// state of the source Bchan after a change.
+type frame[T any] struct {
+	Seq uint64 `json:"seq"`
+	On  bool   `json:"on"`
+	Val T      `json:"val"`
+}
+
+type watchRequest struct{}
+
+var watchStream = grpc.StreamDesc{
+	StreamName:    "Watch",
+	ServerStreams: true,
+}
+
+// Server serves one Bchan to remote Mirrors.
+type Server[T any] struct {
+	b *bchan.Of[T]
+}
+
+// NewServer makes a Server for b. Register
+// it on a grpc.Server to expose b.
+func NewServer[T any](b *bchan.Of[T]) *Server[T] {
+	return &Server[T]{b: b}
+}
+
+// Register adds the Watch service to gs.
+func (s *Server[T]) Register(gs grpc.ServiceRegistrar) {
+	stream := watchStream
+	stream.Handler = s.watch
+	gs.RegisterService(&grpc.ServiceDesc{
+		ServiceName: ServiceName,
+		HandlerType: (*interface{})(nil),
+		Streams:     []grpc.StreamDesc{stream},
+	}, s)
+}
+
+// watch streams the current state, then every
+// change, until the client goes away or the
+// Bchan is closed.
+func (s *Server[T]) watch(_ interface{}, stream grpc.ServerStream) error {
+	var req watchRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+	err := s.b.Follow(stream.Context(), func(v T, seq uint64, on bool) error {
+		return stream.SendMsg(&frame[T]{Seq: seq, On: on, Val: v})
+	})
+	if errors.Is(err, bchan.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// Mirror makes into follow the Bchan served at
+// the other end of cc: each new value is Bcast on
+// into (or just Set, if the source is off), and
+// the source turning on or off does the same to
+// into. It blocks until ctx is done, the stream
+// fails, or it ends because into or the server's
+// Bchan was closed, in which case it returns nil.
+// into is left as it was last set.
+func Mirror[T any](ctx context.Context, cc grpc.ClientConnInterface, into *bchan.Of[T]) error {
+	stream, err := cc.NewStream(ctx, &watchStream, watchMethod, grpc.CallContentSubtype(codecName))
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(&watchRequest{}); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	var seq uint64
+	for {
+		var f frame[T]
+		if err := stream.RecvMsg(&f); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if into.ApplyState(f.Val, f.Seq != seq, f.On) == bchan.ErrClosed {
+			return nil
+		}
+		seq = f.Seq
+	}
+}
@@ -0,0 +1,105 @@
+package grpcbridge_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/grpcbridge"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestMirror(t *testing.T) {
+
+	src := bchan.NewOf[string](1)
+	src.Bcast("v1")
+
+	lis := bufconn.Listen(1 << 16)
+	gs := grpc.NewServer()
+	grpcbridge.NewServer(src).Register(gs)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+
+	dst := bchan.NewOf[string](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go grpcbridge.Mirror(ctx, cc, dst)
+
+	want := func(v string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if got, ok := dst.Cur(); ok && got == v {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("mirror never got %q", v)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	want("v1")
+	src.Bcast("v2")
+	want("v2")
+
+	if v := <-dst.Ch; v != "v2" {
+		t.Fatalf("got %q from the mirror's Ch, want v2", v)
+	}
+	dst.BcastAck()
+}
+
+func TestMirrorIntoClosed(t *testing.T) {
+
+	src := bchan.NewOf[string](1)
+	src.Bcast("v1")
+
+	lis := bufconn.Listen(1 << 16)
+	gs := grpc.NewServer()
+	grpcbridge.NewServer(src).Register(gs)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+
+	dst := bchan.NewOf[string](1)
+	done := make(chan error, 1)
+	go func() { done <- grpcbridge.Mirror(context.Background(), cc, dst) }()
+	if v := <-dst.Ch; v != "v1" {
+		t.Fatalf("got %q, want v1", v)
+	}
+
+	// with the stream live, the next frame finds
+	// dst closed: Mirror must end, not panic.
+	dst.Close()
+	src.Bcast("v2")
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Mirror into a closed Bchan returned %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Mirror went on after its Bchan was closed")
+	}
+}
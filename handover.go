@@ -0,0 +1,173 @@
+package bchan
+
+// Moved returns a channel that is closed the next
+// time b's Ch is replaced, by Resize or Shrink, or
+// when b is closed, so that a receiver holding its
+// own copy of Ch knows to read b.Ch (or RecvCh)
+// afresh. The replaced Ch is never closed before b
+// is (see Resize), so once it stops being served a
+// receive from it just blocks, and Moved is how to
+// tell:
+//
+//	ch := b.RecvCh()
+//	moved := b.Moved()
+//	for {
+//		select {
+//		case v, ok := <-ch:
+//			if !ok {
+//				return // b is closed
+//			}
+//			b.BcastAck()
+//			...
+//		case <-moved:
+//			ch, moved = b.RecvCh(), b.Moved()
+//		}
+//	}
+func (b *Of[T]) Moved() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return closedCh
+	}
+	if b.moved == nil {
+		b.moved = make(chan struct{})
+	}
+	return b.moved
+}
+
+// replaceCh swaps in a new Ch for an
+// expectedDiameter of n, handing over from
+// the old one: it is retired rather than
+// abandoned. A retired Ch is drained, and
+// then kept in service alongside the new one,
+// so receivers still blocked on it, or coming
+// back to it, are not stranded: while b is
+// broadcasting it holds one copy of the value,
+// topped up on every On, Bcast and BcastAck,
+// and it is drained along with Ch. It stops being
+// served once a whole value has gone by without
+// anyone taking a copy from it, and is closed only
+// when b is.
+// Caller must hold b.mu.
+func (b *Of[T]) replaceCh(n int) {
+	old := b.Ch
+	b.setCh(make(chan T, n+1))
+	defer func() {
+		if b.moved != nil {
+			close(b.moved)
+			b.moved = nil
+		}
+	}()
+	if b.onceFilled != nil {
+		b.handOverOnce(old)
+		return
+	}
+	drained := drainCh(old)
+	b.ndrain.Add(drained)
+	b.unfill(drained)
+	b.retired = append(b.retired, &retiredCh[T]{ch: old})
+	b.hasRetired.Store(true)
+	if b.on && b.live {
+		b.fillCh(b.Ch)
+		b.topUpRetired()
+	}
+}
+
+// handOverOnce is the handover in at-most-once
+// mode, where nothing may be handed out twice:
+// the copies still in old move to the new Ch,
+// a value already handed out on old counts as
+// handed out on the new one too, and old is
+// left empty at once rather than retired.
+// Caller must hold b.mu.
+func (b *Of[T]) handOverOnce(old chan T) {
+	if b.onceFilled[old] == b.seq {
+		b.onceFilled[b.Ch] = b.seq
+	}
+	delete(b.onceFilled, old)
+	for {
+		select {
+		case v := <-old:
+			select {
+			case b.Ch <- v:
+			default:
+				b.ndrain.Add(1)
+				b.unfill(1)
+			}
+			continue
+		default:
+		}
+		break
+	}
+	if b.on && b.live {
+		b.fillCh(b.Ch)
+	}
+}
+
+// retiredCh is a Ch that has been replaced.
+// filled counts copies put in it, less those
+// drained, since the value was stored, so
+// that filled > len(ch) means someone took one.
+type retiredCh[T any] struct {
+	ch     chan T
+	filled int
+}
+
+// drainRetired empties r.
+// Caller must hold b.mu.
+func (b *Of[T]) drainRetired(r *retiredCh[T]) {
+	n := drainCh(r.ch)
+	b.ndrain.Add(n)
+	r.filled -= int(n)
+}
+
+// topUpRetired leaves one copy of the value
+// in each retired Ch, unless b is paused.
+// Caller must hold b.mu.
+func (b *Of[T]) topUpRetired() {
+	if b.paused || b.onceFilled != nil {
+		return
+	}
+	for _, r := range b.retired {
+		if len(r.ch) == 0 && b.fillOne(r.ch) {
+			r.filled++
+		}
+	}
+}
+
+// passOverRetired, as a new value is stored,
+// stops serving the retired channels from which
+// not one copy of the value before was taken. They
+// are left open, so that a receive still made on
+// one blocks, as on any idle channel, rather than
+// yielding zero values.
+// Caller must hold b.mu.
+func (b *Of[T]) passOverRetired() {
+	kept := b.retired[:0]
+	for _, r := range b.retired {
+		if len(r.ch) > 0 && r.filled == len(r.ch) {
+			b.drainRetired(r)
+			continue
+		}
+		r.filled = len(r.ch)
+		kept = append(kept, r)
+	}
+	clear(b.retired[len(kept):])
+	b.retired = kept
+	b.hasRetired.Store(len(kept) > 0)
+}
+
+// closeRetired closes every retired channel.
+// Caller must hold b.mu.
+func (b *Of[T]) closeRetired() {
+	for _, r := range b.retired {
+		b.drainRetired(r)
+		close(r.ch)
+	}
+	b.retired = nil
+	b.hasRetired.Store(false)
+	if b.moved != nil {
+		close(b.moved)
+		b.moved = nil
+	}
+}
@@ -0,0 +1,135 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestResizeWakesBlockedReceiver(t *testing.T) {
+
+	bc := bchan.New(1)
+	defer bc.Close()
+	bc.Bcast("bill")
+	old := bc.Ch
+	<-old
+	<-old // empty now; nobody acks
+
+	got := make(chan interface{})
+	go func() { got <- <-old }()
+	time.Sleep(10 * time.Millisecond)
+	bc.Resize(4)
+	select {
+	case v := <-got:
+		if v != "bill" {
+			t.Fatalf("got %v", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("receiver left blocked on the old Ch")
+	}
+}
+
+func TestRetiredChServed(t *testing.T) {
+
+	bc := bchan.New(1)
+	defer bc.Close()
+	bc.Bcast("bill")
+	old := bc.Ch
+	moved := bc.Moved()
+	bc.Resize(4)
+	select {
+	case <-moved:
+	default:
+		t.Fatal("Moved should close on Resize")
+	}
+
+	// a receiver still on the old Ch keeps being served.
+	for i := 0; i < 3; i++ {
+		select {
+		case v := <-old:
+			if v != "bill" {
+				t.Fatalf("got %v", v)
+			}
+			bc.BcastAck()
+		case <-time.After(time.Second):
+			t.Fatalf("old Ch not topped up on receive %d", i)
+		}
+	}
+	bc.Bcast("ted")
+	if v := <-old; v != "ted" {
+		t.Fatalf("old Ch should carry the new value, got %v", v)
+	}
+	if len(bc.Ch) != 5 {
+		t.Fatalf("new Ch should be full, len %d", len(bc.Ch))
+	}
+}
+
+func TestRetiredChLeftOpenWhenPassedOver(t *testing.T) {
+
+	bc := bchan.New(1)
+	bc.Bcast("bill")
+	old := bc.Ch
+	bc.Resize(4)
+
+	bc.Bcast("ted") // nobody took bill from old
+	bc.BcastAck()
+	select {
+	case v, ok := <-old:
+		t.Fatalf("an unused retired Ch should be left open and empty; got %q, %v", v, ok)
+	default:
+	}
+
+	old = bc.Ch
+	bc.Resize(8)
+	bc.Close()
+	if _, ok := <-old; ok {
+		t.Fatal("Close should close retired channels too")
+	}
+	select {
+	case <-bc.Moved():
+	default:
+		t.Fatal("Moved should be closed once b is")
+	}
+}
+
+func TestResizeAtMostOnce(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[int](bchan.WithDiameter(1), bchan.WithAtMostOnce())
+	defer bc.Close()
+	bc.Bcast(7)
+	old := bc.Ch
+	bc.Resize(4)
+
+	n := 0
+	select {
+	case v, ok := <-old:
+		t.Fatalf("old Ch should have handed its copy over, got %v, %v", v, ok)
+	default:
+	}
+	for {
+		v, ok := bc.TryRecv()
+		if !ok {
+			break
+		}
+		if v != 7 {
+			t.Fatalf("got %v", v)
+		}
+		n++
+	}
+	if n != 1 {
+		t.Fatalf("7 was handed out %d times, want once", n)
+	}
+
+	bc.Bcast(8)
+	n = 0
+	for {
+		if _, ok := bc.TryRecv(); !ok {
+			break
+		}
+		n++
+	}
+	if n != 4 {
+		t.Fatalf("8 was handed out %d times, want the new diameter 4", n)
+	}
+}
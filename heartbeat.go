@@ -0,0 +1,54 @@
+package bchan
+
+import (
+	"time"
+)
+
+// SetHeartbeat makes b rebroadcast its current
+// value every d even when it has not changed:
+// Ch is drained and refilled, subscriptions are
+// re-fed, and the freshness time reported by
+// Envelope().Fresh is bumped (on a NewEnveloped
+// Bchan, the Fresh field of the value itself is
+// updated). Receivers using b as a heartbeat can
+// then tell "alive with the same value", whose
+// Fresh keeps moving, from "producer gone".
+// Beats only happen while broadcasting is on.
+// d <= 0 stops the heartbeat.
+func (b *Of[T]) SetHeartbeat(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.beatGen++
+	if b.beatTimer != nil {
+		b.beatTimer.Stop()
+		b.beatTimer = nil
+	}
+	b.beat = d
+	if d > 0 && !b.closed {
+		b.scheduleBeat()
+	}
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) scheduleBeat() {
+	gen := b.beatGen
+	b.beatTimer = b.afterFunc(b.beat, func() { b.heartbeat(gen) })
+}
+
+func (b *Of[T]) heartbeat(gen uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.beatGen || b.closed {
+		return
+	}
+	if b.on && b.live && !b.paused {
+		b.freshAt = b.now()
+		if b.restamp != nil {
+			b.cur = b.restamp(b.cur, b.freshAt)
+		}
+		b.drain()
+		b.fill()
+		b.publish(true)
+	}
+	b.scheduleBeat()
+}
@@ -0,0 +1,31 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestHeartbeat(t *testing.T) {
+
+	bc := bchan.NewEnveloped[string](1)
+	bchan.BcastVal(bc, "same")
+	first := <-bc.Ch
+	bc.BcastAck()
+
+	bc.SetHeartbeat(10 * time.Millisecond)
+	defer bc.SetHeartbeat(0)
+	eventually(t, "a heartbeat", func() bool {
+		return bc.Envelope().Fresh.After(first.Fresh)
+	})
+
+	env := <-bc.Ch
+	bc.BcastAck()
+	if env.Val != "same" || env.Seq != first.Seq {
+		t.Fatalf("heartbeat must not change the value or seq, got %+v", env)
+	}
+	if !env.At.Equal(first.At) {
+		t.Fatal("heartbeat must not change the set time")
+	}
+}
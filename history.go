@@ -0,0 +1,103 @@
+package bchan
+
+// KeepHistory makes b remember the last n
+// values handed to Set or Bcast, for Replay
+// and for subscriptions made WithBacklog.
+// n <= 0 turns history off and forgets it.
+// Resizing keeps the newest entries that fit.
+func (b *Of[T]) KeepHistory(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n <= 0 {
+		b.hist = nil
+		return
+	}
+	old := b.hist.last(n)
+	b.hist = newRing[T](n)
+	for _, v := range old {
+		b.hist.push(v)
+	}
+}
+
+// Replay returns up to n of the most
+// recently broadcast values, oldest first.
+// It returns nil if KeepHistory is off.
+func (b *Of[T]) Replay(n int) []T {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.hist.last(n)
+}
+
+// remember records val in the history, if on.
+// Caller must hold b.mu.
+func (b *Of[T]) remember(val T) {
+	if b.hist != nil {
+		b.hist.push(val)
+	}
+}
+
+// ring is a fixed size circular buffer.
+type ring[T any] struct {
+	buf  []T
+	next int
+	full bool
+}
+
+func newRing[T any](n int) *ring[T] {
+	return &ring[T]{buf: make([]T, n)}
+}
+
+func (r *ring[T]) push(v T) {
+	r.buf[r.next] = v
+	r.next++
+	if r.next == len(r.buf) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+func (r *ring[T]) len() int {
+	if r.full {
+		return len(r.buf)
+	}
+	return r.next
+}
+
+// last returns a copy of the newest n
+// entries, oldest first. A nil ring
+// returns nil.
+func (r *ring[T]) last(n int) []T {
+	if r == nil || n <= 0 {
+		return nil
+	}
+	k := r.len()
+	if n > k {
+		n = k
+	}
+	out := make([]T, n)
+	start := r.next - n
+	if start < 0 {
+		start += len(r.buf)
+	}
+	for i := range out {
+		out[i] = r.buf[(start+i)%len(r.buf)]
+	}
+	return out
+}
+
+// Window returns up to n of the most recently
+// broadcast values, oldest first, together with a
+// new subscription (as if from Subscribe(opts...))
+// that picks up exactly where they leave off, both
+// taken at the same instant so that nothing falls in
+// between. A "recent history" view can render the
+// window and then follow s.Ch. The last of recent is
+// the current value, which s also delivers. Like
+// Replay, Window needs KeepHistory; without it recent
+// is nil.
+func (b *Of[T]) Window(n int, opts ...SubOption) (recent []T, s *SubscriptionOf[T]) {
+	cfg := subOptions(opts)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.hist.last(n), b.subscribe(cfg)
+}
@@ -0,0 +1,73 @@
+package bchan_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestReplay(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	if bc.Replay(3) != nil {
+		t.Fatal("history is off by default")
+	}
+	bc.KeepHistory(3)
+	for i := 1; i <= 5; i++ {
+		bc.Bcast(i)
+	}
+	if got := bc.Replay(10); !reflect.DeepEqual(got, []int{3, 4, 5}) {
+		t.Fatalf("expected last 3 values oldest first, got %v", got)
+	}
+	if got := bc.Replay(2); !reflect.DeepEqual(got, []int{4, 5}) {
+		t.Fatalf("expected [4 5], got %v", got)
+	}
+
+	bc.KeepHistory(2)
+	if got := bc.Replay(10); !reflect.DeepEqual(got, []int{4, 5}) {
+		t.Fatalf("shrinking history should keep newest, got %v", got)
+	}
+}
+
+func TestSubscribeWithBacklog(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.KeepHistory(10)
+	for i := 1; i <= 4; i++ {
+		bc.Bcast(i)
+	}
+
+	sub := bc.Subscribe(bchan.WithBacklog(3))
+	defer bc.Unsubscribe(sub)
+
+	var got []int
+	for i := 0; i < 5; i++ {
+		got = append(got, <-sub.Ch)
+	}
+	// backlog 2,3,4 then the sticky current value 4.
+	if !reflect.DeepEqual(got, []int{2, 3, 4, 4, 4}) {
+		t.Fatalf("unexpected delivery order %v", got)
+	}
+}
+
+func TestWindow(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.KeepHistory(10)
+	for i := 1; i <= 4; i++ {
+		bc.Bcast(i)
+	}
+	recent, sub := bc.Window(3, bchan.WithQueue(10))
+	defer bc.Unsubscribe(sub)
+	if len(recent) != 3 || recent[0] != 2 || recent[2] != 4 {
+		t.Fatalf("got window %v, want [2 3 4]", recent)
+	}
+	if v := <-sub.Ch; v != 4 {
+		t.Fatalf("subscription started at %d, want the current value 4", v)
+	}
+	bc.Bcast(5)
+	if v := <-sub.Ch; v != 5 {
+		t.Fatalf("got %d, want 5", v)
+	}
+}
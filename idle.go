@@ -0,0 +1,46 @@
+package bchan
+
+import (
+	"time"
+)
+
+// SetIdleTimeout arms a watchdog for liveness-style
+// signals, where a stale value is worse than none:
+// if d passes with no Set or Bcast, broadcasting is
+// turned Off automatically, which also notifies
+// anyone waiting on Stopped(). Each Set or Bcast
+// restarts the countdown. d <= 0 disarms it.
+func (b *Of[T]) SetIdleTimeout(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.idle = d
+	b.kickIdle()
+}
+
+// kickIdle restarts the idle watchdog, if armed.
+// Caller must hold b.mu.
+func (b *Of[T]) kickIdle() {
+	if b.idleTimer != nil {
+		b.idleTimer.Stop()
+		b.idleTimer = nil
+	}
+	if b.idle <= 0 || b.closed {
+		return
+	}
+	b.idleGen++
+	gen := b.idleGen
+	b.idleTimer = b.afterFunc(b.idle, func() { b.idleExpired(gen) })
+}
+
+func (b *Of[T]) idleExpired(gen uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.idleGen || b.closed {
+		return
+	}
+	b.idleTimer = nil
+	if b.on {
+		b.logf("bchan: idle timeout after %v", b.idle)
+		b.off()
+	}
+}
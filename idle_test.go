@@ -0,0 +1,34 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestIdleTimeout(t *testing.T) {
+
+	bc := bchan.NewWithOptions(bchan.WithIdleTimeout(40 * time.Millisecond))
+	bc.Bcast("alive")
+	stopped := bc.Stopped()
+
+	// keep it fed past the timeout...
+	for i := 0; i < 4; i++ {
+		time.Sleep(20 * time.Millisecond)
+		bc.Bcast("alive")
+	}
+	if !bc.IsOn() {
+		t.Fatal("a fed Bchan should stay on")
+	}
+
+	// ...then go quiet.
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle Bchan should have turned itself off")
+	}
+	if bc.IsOn() || len(bc.Ch) != 0 {
+		t.Fatal("expected off and drained")
+	}
+}
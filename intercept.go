@@ -0,0 +1,145 @@
+package bchan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDropped is returned by a broadcast that a
+// BcastInterceptor swallowed by returning nil
+// without calling next.
+var ErrDropped = errors.New("bchan: value dropped by interceptor")
+
+// BcastInterceptor wraps every broadcast of a new
+// value, much as a gRPC interceptor wraps a call.
+// It is given the name of the broadcasting method
+// (such as "Bcast" or "Set"), the value, and next,
+// which passes the value, changed if need be, on
+// towards the rest of the chain and then the
+// validator (see SetValidator). To reject the value,
+// return an error without calling next; it is then
+// reported as a validation error would be. Returning
+// nil without calling next drops the value quietly,
+// though methods that report errors return ErrDropped.
+type BcastInterceptor[T any] func(op string, v T, next func(v T) error) error
+
+// DeliverInterceptor wraps each hand-out of the value
+// to a receiver: each copy placed into Ch or a consumer
+// group's Ch, and each value sent to a subscription or
+// by Pulse. It returns what the receiver gets, usually
+// next(v), or something derived from it. Delivery
+// cannot be refused; reject values at broadcast time
+// with a BcastInterceptor instead.
+type DeliverInterceptor[T any] func(v T, next func(v T) T) T
+
+// InterceptBcast adds ics to b's chain of broadcast
+// interceptors. The chain runs in the order the
+// interceptors were added, the first outermost,
+// with b locked; they must not call b's methods.
+func (b *Of[T]) InterceptBcast(ics ...BcastInterceptor[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bcastICs = append(b.bcastICs, ics...)
+}
+
+// InterceptDeliver adds ics to b's chain of delivery
+// interceptors, which runs like InterceptBcast's, and
+// innermost of all makes any copy asked for by
+// SetClone. Delivery interceptors run once per
+// hand-out, on whichever goroutine is doing it, so
+// they need to be cheap and safe for concurrent use.
+func (b *Of[T]) InterceptDeliver(ics ...DeliverInterceptor[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deliverICs = append(b.deliverICs, ics...)
+	b.rebuildOut()
+}
+
+// admit runs the broadcast interceptors, reducer
+// and validator on *val for op, leaving in *val the
+// value to store, or returning why not to.
+// Caller must hold b.mu.
+func (b *Of[T]) admit(op string, val *T) error {
+	return b.admitting(op, val, true)
+}
+
+// admitWhole is admit for a val that is to replace
+// the current value outright, whatever the reducer.
+// Caller must hold b.mu.
+func (b *Of[T]) admitWhole(op string, val *T) error {
+	return b.admitting(op, val, false)
+}
+
+// admitting is admit, counting the value as
+// dropped if it is refused. Caller must hold b.mu.
+func (b *Of[T]) admitting(op string, val *T, reduce bool) error {
+	err := b.intercepted(op, val, reduce)
+	if err != nil {
+		b.ndropped.Add(1)
+	}
+	return err
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) intercepted(op string, val *T, reduce bool) error {
+	if len(b.bcastICs) == 0 {
+		if reduce {
+			*val = b.reduce(*val)
+		}
+		return b.validate(op, *val)
+	}
+	var reached bool
+	var verr error
+	next := func(v T) error {
+		reached = true
+		if reduce {
+			v = b.reduce(v)
+		}
+		*val = v
+		verr = b.validate(op, v)
+		return verr
+	}
+	for i := len(b.bcastICs) - 1; i >= 0; i-- {
+		ic, inner := b.bcastICs[i], next
+		next = func(v T) error { return ic(op, v, inner) }
+	}
+	err := next(*val)
+	switch {
+	case err == verr && verr != nil:
+		return verr
+	case err != nil:
+		b.logf("bchan: %s rejected by interceptor: %v", op, err)
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	case !reached:
+		b.logf("bchan: %s dropped by interceptor", op)
+		return ErrDropped
+	}
+	return verr
+}
+
+// rebuildOut recomposes b.out from the clone func
+// and delivery interceptors. Caller must hold b.mu.
+func (b *Of[T]) rebuildOut() {
+	if b.clone == nil && len(b.deliverICs) == 0 {
+		b.out = nil
+		return
+	}
+	next := func(v T) T { return v }
+	if b.clone != nil {
+		next = b.clone
+	}
+	for i := len(b.deliverICs) - 1; i >= 0; i-- {
+		ic, inner := b.deliverICs[i], next
+		next = func(v T) T { return ic(v, inner) }
+	}
+	b.out = next
+}
+
+// handout returns what a receiver should be given
+// for v. Caller must hold b.mu.
+func (b *Of[T]) handout(v T) T {
+	if b.out == nil {
+		return v
+	}
+	return b.out(v)
+}
@@ -0,0 +1,78 @@
+package bchan_test
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestInterceptBcast(t *testing.T) {
+
+	bc := bchan.NewOf[string](1)
+	var order []string
+	errShout := errors.New("no shouting")
+	bc.InterceptBcast(
+		func(op string, v string, next func(string) error) error {
+			order = append(order, "log:"+op)
+			return next(v)
+		},
+		func(op string, v string, next func(string) error) error {
+			if v == "DROP" {
+				return nil
+			}
+			if strings.HasSuffix(v, "!") {
+				return errShout
+			}
+			return next(strings.TrimSpace(v))
+		},
+	)
+
+	bc.Bcast("  v1  ")
+	if v := <-bc.Ch; v != "v1" {
+		t.Fatalf("got %q, want the trimmed value", v)
+	}
+	bc.BcastAck()
+	if len(order) != 1 || order[0] != "log:Bcast" {
+		t.Fatalf("outer interceptor saw %v", order)
+	}
+
+	if err := bc.TryBcast("v2!"); !errors.Is(err, errShout) || !errors.Is(err, bchan.ErrInvalid) {
+		t.Fatalf("got %v, want the interceptor's rejection", err)
+	}
+	if err := bc.TryBcast("DROP"); err != bchan.ErrDropped {
+		t.Fatalf("got %v, want ErrDropped", err)
+	}
+	if v := bc.Get(); v != "v1" {
+		t.Fatalf("refused values changed the current value to %q", v)
+	}
+}
+
+func TestInterceptDeliver(t *testing.T) {
+
+	bc := bchan.NewOf[string](2)
+	var n atomic.Int64
+	bc.InterceptDeliver(func(v string, next func(string) string) string {
+		n.Add(1)
+		return strings.ToUpper(next(v))
+	})
+	bc.Bcast("hi")
+	if v := <-bc.Ch; v != "HI" {
+		t.Fatalf("got %q from Ch, want HI", v)
+	}
+	bc.BcastAck()
+
+	sub := bc.Subscribe()
+	defer bc.Unsubscribe(sub)
+	if v := <-sub.Ch; v != "HI" {
+		t.Fatalf("got %q from a subscription, want HI", v)
+	}
+	if bc.Get() != "hi" {
+		t.Fatal("the stored value must be left alone")
+	}
+	if n.Load() < 4 {
+		t.Fatalf("interceptor ran %d times, want once per hand-out", n.Load())
+	}
+}
@@ -0,0 +1,137 @@
+package bchan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvariant is wrapped by every error
+// CheckInvariants returns.
+var ErrInvariant = errors.New("bchan: invariant violated")
+
+// WithInvariantCheck has b run CheckInvariants after
+// every change of state, passing report any error,
+// for canaries and fuzz tests that want a broken
+// Bchan caught at the moment it breaks. report runs
+// with b locked, so it must not call back into b.
+// The checks are cheap, but not free.
+func WithInvariantCheck(report func(err error)) Option {
+	return func(c *config) {
+		c.invReport = report
+	}
+}
+
+// CheckInvariants checks b's internal consistency:
+//
+//   - the sequence number never goes backwards
+//     between checks, except by UnmarshalBinary;
+//   - a closed Bchan is off;
+//   - while off or paused, Ch, every consumer group
+//     channel, and any Ch retired by Resize, are
+//     empty;
+//   - while on, Ch holds only copies of the current
+//     value, as receivers are handed it, and no more
+//     than were put there since it was Set;
+//   - no more acks are counted for the current value
+//     than in all, and no more drains than fills;
+//   - no registered receiver has seen a sequence
+//     number beyond the current one.
+//
+// It returns an error wrapping ErrInvariant for
+// the first one that fails, or nil.
+func (b *Of[T]) CheckInvariants() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.checkInvariants()
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) checkInvariants() error {
+	bad := func(format string, v ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvariant, fmt.Sprintf(format, v...))
+	}
+	if b.seq < b.checkedSeq {
+		return bad("seq went back from %d to %d", b.checkedSeq, b.seq)
+	}
+	b.checkedSeq = b.seq
+	if b.closed && b.on {
+		return bad("closed but on")
+	}
+	if !b.on || b.paused {
+		if n := len(b.Ch); n > 0 {
+			return bad("not broadcasting, but Ch holds %d", n)
+		}
+		for name, g := range b.groups {
+			if n := len(g.Ch); n > 0 {
+				return bad("not broadcasting, but group %q holds %d", name, n)
+			}
+		}
+		for _, r := range b.retired {
+			if n := len(r.ch); n > 0 {
+				return bad("not broadcasting, but a retired Ch holds %d", n)
+			}
+		}
+	} else {
+		if n := uint64(len(b.Ch)); n > b.seqFills {
+			return bad("Ch holds %d, but only %d were filled since seq=%d", n, b.seqFills, b.seq)
+		}
+		if !b.chHoldsCur() {
+			return bad("Ch holds a value other than seq=%d", b.seq)
+		}
+	}
+	acks := b.seqAcks.Load()
+	if all := b.nack.Load(); acks > all {
+		return bad("%d acks of seq=%d, but %d in all", acks, b.seq, all)
+	}
+	drains := b.ndrain.Load()
+	if fills := b.nfill.Load(); drains > fills {
+		return bad("%d drains, but %d fills", drains, fills)
+	}
+	for name, r := range b.receivers {
+		if r.lastSeq > b.seq {
+			return bad("receiver %q saw seq=%d, beyond seq=%d", name, r.lastSeq, b.seq)
+		}
+	}
+	return nil
+}
+
+// chHoldsCur reports whether every value waiting
+// in Ch is a copy of the current one. It takes them
+// out to look, and puts them back in order; only
+// fills, which need b.mu, could get in between.
+// Delivery interceptors may hand out anything, so
+// with any installed it cannot tell, and says yes.
+// Caller must hold b.mu.
+func (b *Of[T]) chHoldsCur() bool {
+	if len(b.deliverICs) > 0 {
+		return true
+	}
+	var held []T
+	for len(held) < cap(b.Ch) {
+		select {
+		case v := <-b.Ch:
+			held = append(held, v)
+			continue
+		default:
+		}
+		break
+	}
+	ok := true
+	for _, v := range held {
+		ok = ok && b.eq(v, b.cur)
+		b.Ch <- v
+	}
+	return ok
+}
+
+// verify runs the checks asked for by
+// WithInvariantCheck.
+// Caller must hold b.mu.
+func (b *Of[T]) verify() {
+	if b.invReport == nil {
+		return
+	}
+	if err := b.checkInvariants(); err != nil {
+		b.invReport(err)
+	}
+}
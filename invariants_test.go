@@ -0,0 +1,101 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestCheckInvariants(t *testing.T) {
+
+	var failed error
+	bc := bchan.NewOfWithOptions[int](bchan.WithDiameter(2),
+		bchan.WithInvariantCheck(func(err error) { failed = err }))
+	check := func(when string) {
+		t.Helper()
+		if err := bc.CheckInvariants(); err != nil {
+			t.Fatalf("%s: %v", when, err)
+		}
+		if failed != nil {
+			t.Fatalf("%s: always-on check reported %v", when, failed)
+		}
+	}
+	check("new")
+	bc.Bcast(1)
+	check("Bcast")
+	<-bc.Ch
+	check("receive")
+	bc.BcastAck()
+	check("ack")
+	bc.Pause()
+	check("Pause")
+	bc.Resume()
+	check("Resume")
+	<-bc.Ch
+	bc.Set(2)
+	check("Set")
+	bc.BcastAck()
+	check("ack after Set")
+	if v := <-bc.Ch; v != 2 {
+		t.Fatalf("an ack refills Ch with the current value; got %d", v)
+	}
+	bc.On()
+	check("On")
+	bc.Group("g")
+	bc.Off()
+	check("Off")
+	bc.Close()
+	check("Close")
+}
+
+func TestCheckInvariantsAfterRestore(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(1)
+	snap, err := bc.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc.Bcast(2)
+	if err := bc.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bc.UnmarshalBinary(snap); err != nil {
+		t.Fatal(err)
+	}
+	if err := bc.CheckInvariants(); err != nil {
+		t.Fatalf("a restore may rewind seq: %v", err)
+	}
+}
+
+func TestCheckInvariantsNoHandouts(t *testing.T) {
+
+	var clones, handouts uint64
+	bc := bchan.NewOfWithOptions[[]int](bchan.WithDiameter(2),
+		bchan.WithClone(func(v []int) []int {
+			clones++
+			return append([]int(nil), v...)
+		}),
+		bchan.WithInvariantCheck(func(err error) { t.Errorf("invariant: %v", err) }))
+	bc.Bcast([]int{1})
+	<-bc.Ch
+	bc.BcastAck()
+	if err := bc.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	// one copy as it is stored, and one per fill.
+	if fills := bc.Counters().Fills; clones != fills+1 {
+		t.Fatalf("checking cloned %d times for %d fills", clones, fills)
+	}
+
+	bc.InterceptDeliver(func(v []int, next func(v []int) []int) []int {
+		handouts++
+		return next(v)
+	})
+	clones = 0
+	before := bc.Counters().Fills
+	bc.Bcast([]int{2})
+	if fills := bc.Counters().Fills - before; handouts != fills || clones != fills+1 {
+		t.Fatalf("checking ran %d interceptors and %d clones for %d fills", handouts, clones, fills)
+	}
+}
@@ -0,0 +1,162 @@
+package bchan
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// JournalEntry is one line of a Bchan's journal.
+// See JournalEntryOf.
+type JournalEntry = JournalEntryOf[interface{}]
+
+// JournalEntryOf records the state of a Bchan
+// after one transition: a new value from Set or
+// Bcast, or broadcasting being turned on or off.
+// At is when the transition happened; Seq and Val
+// are as reported by Envelope at that moment.
+type JournalEntryOf[T any] struct {
+	Seq uint64    `json:"seq"`
+	At  time.Time `json:"at"`
+	On  bool      `json:"on"`
+	Val T         `json:"val"`
+}
+
+// Journal starts appending a JournalEntry to w,
+// as one line of JSON or in b's Codec if it has
+// been given one (see SetCodec), after every state
+// transition of b, for post-mortem analysis or
+// replay. Writes are made synchronously, with b
+// locked, so a slow w slows every producer; wrap
+// it in a bufio.Writer if that matters and it
+// need not survive a crash. If a write fails, the
+// error is logged (see WithLogger) and journaling
+// stops. Journal(nil) stops it too.
+func (b *Of[T]) Journal(w io.Writer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeJournal()
+	if w != nil {
+		b.journal = b.codecOr(JSONCodec).NewEncoder(w)
+	}
+}
+
+// JournalToFile is Journal to the file at path,
+// created if need be and appended to otherwise.
+// The file is closed when b is, or when the
+// journal is redirected.
+func (b *Of[T]) JournalToFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeJournal()
+	b.journal = b.codecOr(JSONCodec).NewEncoder(f)
+	b.journalFile = f
+	return nil
+}
+
+// record journals b's state if it has changed
+// since the last entry. Caller must hold b.mu.
+func (b *Of[T]) record() {
+	if b.journal == nil {
+		return
+	}
+	on := b.on && b.live
+	if b.journaled && b.seq == b.journalSeq && on == b.journalOn {
+		return
+	}
+	b.journaled, b.journalSeq, b.journalOn = true, b.seq, on
+	e := JournalEntryOf[T]{Seq: b.seq, At: b.now(), On: on, Val: b.cur}
+	if err := b.journal.Encode(&e); err != nil {
+		b.logf("bchan: journal write failed, journaling stopped: %v", err)
+		b.closeJournal()
+	}
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) closeJournal() {
+	b.journal = nil
+	b.journaled = false
+	if b.journalFile != nil {
+		b.journalFile.Close()
+		b.journalFile = nil
+	}
+}
+
+// ReplayJournal reads a journal written by Journal
+// from r, in b's Codec, and plays its transitions
+// into b, so a new
+// node can step through the state history before
+// switching over to live broadcasts. speed scales
+// the original pacing: 1 keeps the recorded gaps
+// between entries, 10 plays them ten times as fast,
+// and 0 plays them back to back. New values are
+// stored immediately, never debounced, and get b's
+// own sequence numbers. ReplayJournal returns nil at
+// the end of r, ctx.Err() if ctx is done first, or
+// ErrClosed if b is closed.
+func (b *Of[T]) ReplayJournal(ctx context.Context, r io.Reader, speed float64) error {
+	b.mu.Lock()
+	dec := b.codecOr(JSONCodec).NewDecoder(r)
+	b.mu.Unlock()
+	var prev JournalEntryOf[T]
+	for first := true; ; first = false {
+		var e JournalEntryOf[T]
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if !first && speed > 0 {
+			gap := time.Duration(float64(e.At.Sub(prev.At)) / speed)
+			if gap > 0 {
+				due, t := b.timeout(gap)
+				select {
+				case <-due:
+				case <-ctx.Done():
+					t.Stop()
+					return ctx.Err()
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !b.playEntry(first || e.Seq != prev.Seq, e) {
+			return ErrClosed
+		}
+		prev = e
+	}
+}
+
+// playEntry applies one journal entry, reporting
+// false if b is closed.
+func (b *Of[T]) playEntry(changed bool, e JournalEntryOf[T]) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return false
+	}
+	switch {
+	case changed:
+		b.cancelPending()
+		b.store("ReplayJournal", e.Val)
+		if e.On {
+			b.activate()
+		} else {
+			b.turnOff()
+			b.drain()
+			b.publish(false)
+		}
+	case e.On && !b.on:
+		b.turnOn()
+	case !e.On && b.on:
+		b.off()
+	}
+	return true
+}
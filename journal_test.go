@@ -0,0 +1,102 @@
+package bchan_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func readJournal(t *testing.T, data []byte) (es []bchan.JournalEntryOf[string]) {
+	t.Helper()
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var e bchan.JournalEntryOf[string]
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		es = append(es, e)
+	}
+	return es
+}
+
+func TestJournal(t *testing.T) {
+
+	var buf bytes.Buffer
+	bc := bchan.NewOfWithOptions[string](bchan.WithJournal(&buf), bchan.WithInitial("v0"))
+	bc.On()
+	bc.Bcast("v1")
+	bc.Off()
+	bc.Off() // no transition, no entry
+
+	type want struct {
+		seq uint64
+		on  bool
+		val string
+	}
+	wants := []want{{1, false, "v0"}, {1, true, "v0"}, {2, true, "v1"}, {2, false, "v1"}}
+	es := readJournal(t, buf.Bytes())
+	if len(es) != len(wants) {
+		t.Fatalf("got %d entries, want %d: %+v", len(es), len(wants), es)
+	}
+	for i, w := range wants {
+		e := es[i]
+		if e.Seq != w.seq || e.On != w.on || e.Val != w.val || e.At.IsZero() {
+			t.Fatalf("entry %d is %+v, want %+v", i, e, w)
+		}
+	}
+}
+
+func TestJournalToFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "bchan.journal")
+	bc := bchan.NewOf[string](1)
+	if err := bc.JournalToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	bc.Bcast("v1")
+	bc.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := readJournal(t, data)
+	if len(es) != 2 || es[0].Val != "v1" || !es[0].On || es[1].On {
+		t.Fatalf("got %+v, want v1 on then off at Close", es)
+	}
+}
+
+func TestReplayJournal(t *testing.T) {
+
+	var buf bytes.Buffer
+	src := bchan.NewOf[string](1)
+	src.Journal(&buf)
+	src.Bcast("v1")
+	src.Bcast("v2")
+	src.Off()
+
+	dst := bchan.NewOf[string](1)
+	dst.KeepHistory(10)
+	if err := dst.ReplayJournal(context.Background(), &buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, on := dst.Cur(); on || v != "v2" {
+		t.Fatalf("after replay got %q on=%v, want v2 off", v, on)
+	}
+	if h := dst.Replay(10); len(h) != 2 || h[0] != "v1" || h[1] != "v2" {
+		t.Fatalf("replayed history %v, want [v1 v2]", h)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := dst.ReplayJournal(ctx, strings.NewReader(`{"seq":1,"val":"x"}`), 1); err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,70 @@
+package kv
+
+import (
+	"github.com/glycerine/bchan"
+)
+
+// EventType says what happened to a key.
+type EventType int
+
+const (
+	Added EventType = iota + 1
+	Modified
+	Deleted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case Added:
+		return "Added"
+	case Modified:
+		return "Modified"
+	case Deleted:
+		return "Deleted"
+	}
+	return "EventType(?)"
+}
+
+// Event is one change to a Store, in the style of a
+// Kubernetes watch event. For Deleted, Object is the
+// value the key had.
+type Event[K comparable, V any] struct {
+	Type   EventType
+	Key    K
+	Object V
+}
+
+// WatchEvents lists the whole Store and starts an
+// event stream, together and atomically, so that a
+// controller-style consumer can load list into a
+// local cache and then keep it current by applying
+// each Event from sub.Ch, missing nothing and
+// seeing nothing twice. list holds an Added event per
+// key present. sub is a queue subscription (see
+// bchan.WithQueue) holding up to queue events, plus
+// any other opts, that starts after list (see
+// bchan.WithoutCurrent); if the consumer falls so far
+// behind that sub.Dropped() goes up, its cache is
+// stale and it should call UnwatchEvents and then
+// WatchEvents again.
+// Release sub with UnwatchEvents.
+func (s *Store[K, V]) WatchEvents(queue int, opts ...bchan.SubOption) (list []Event[K, V], sub *bchan.SubscriptionOf[Event[K, V]]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		panic("kv: WatchEvents called on closed Store")
+	}
+	for k, sl := range s.slots {
+		if sl.present {
+			list = append(list, Event[K, V]{Type: Added, Key: k, Object: sl.b.Get()})
+		}
+	}
+	opts = append([]bchan.SubOption{bchan.WithQueue(queue), bchan.WithoutCurrent()}, opts...)
+	return list, s.events.Subscribe(opts...)
+}
+
+// UnwatchEvents ends sub, a subscription from
+// WatchEvents, closing its Ch.
+func (s *Store[K, V]) UnwatchEvents(sub *bchan.SubscriptionOf[Event[K, V]]) {
+	s.events.Unsubscribe(sub)
+}
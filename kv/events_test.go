@@ -0,0 +1,52 @@
+package kv_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan/kv"
+)
+
+func TestWatchEvents(t *testing.T) {
+
+	s := kv.New[string, int]()
+	defer s.Close()
+	s.Set("a", 1)
+	s.Set("b", 2)
+
+	list, sub := s.WatchEvents(16)
+	defer s.UnwatchEvents(sub)
+	cache := make(map[string]int)
+	for _, e := range list {
+		if e.Type != kv.Added {
+			t.Fatalf("list holds a %v event", e.Type)
+		}
+		cache[e.Key] = e.Object
+	}
+	if len(cache) != 2 {
+		t.Fatalf("listed %v", cache)
+	}
+
+	s.Set("a", 10)
+	s.Set("c", 3)
+	s.Delete("b")
+	want := []kv.Event[string, int]{
+		{Type: kv.Modified, Key: "a", Object: 10},
+		{Type: kv.Added, Key: "c", Object: 3},
+		{Type: kv.Deleted, Key: "b", Object: 2},
+	}
+	for _, w := range want {
+		e := <-sub.Ch
+		if e != w {
+			t.Fatalf("got %+v, want %+v", e, w)
+		}
+		switch e.Type {
+		case kv.Deleted:
+			delete(cache, e.Key)
+		default:
+			cache[e.Key] = e.Object
+		}
+	}
+	if len(cache) != 2 || cache["a"] != 10 || cache["c"] != 3 {
+		t.Fatalf("cache is %v", cache)
+	}
+}
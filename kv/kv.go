@@ -0,0 +1,179 @@
+// package kv is a concurrent map whose keys can each
+// be watched: Set(k, v) wakes only the watchers of k.
+//
+// Every key is backed by its own bchan.Of[V], so a
+// watcher gets the usual latest-value-wins delivery,
+// and any bchan.SubOption (a filter, a queue) can be
+// passed to Watch.
+package kv
+
+import (
+	"sync"
+
+	"github.com/glycerine/bchan"
+)
+
+// Store maps keys of type K to values of type V.
+// The zero Store is not usable; call New.
+type Store[K comparable, V any] struct {
+	mu     sync.Mutex
+	slots  map[K]*slot[V]
+	closed bool
+
+	// events carries an Event per change,
+	// for WatchEvents.
+	events *bchan.Of[Event[K, V]]
+}
+
+// slot is one key's Bchan. A key that has been
+// deleted, or only ever watched, has present false;
+// its slot is kept only while it has watchers.
+type slot[V any] struct {
+	b       *bchan.Of[V]
+	watches map[*bchan.SubscriptionOf[V]]bool
+	present bool
+}
+
+// New makes an empty Store.
+func New[K comparable, V any]() *Store[K, V] {
+	return &Store[K, V]{
+		slots:  make(map[K]*slot[V]),
+		events: bchan.NewOf[Event[K, V]](1),
+	}
+}
+
+// caller must hold s.mu.
+func (s *Store[K, V]) slot(k K) *slot[V] {
+	sl, ok := s.slots[k]
+	if !ok {
+		sl = &slot[V]{b: bchan.NewOf[V](1), watches: make(map[*bchan.SubscriptionOf[V]]bool)}
+		s.slots[k] = sl
+	}
+	return sl
+}
+
+// Set makes v the value of k and
+// broadcasts it to k's watchers.
+func (s *Store[K, V]) Set(k K, v V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		panic("kv: Set called on closed Store")
+	}
+	sl := s.slot(k)
+	typ := Modified
+	if !sl.present {
+		typ = Added
+	}
+	sl.present = true
+	sl.b.Bcast(v)
+	s.events.Bcast(Event[K, V]{Type: typ, Key: k, Object: v})
+}
+
+// Get returns the value of k, and whether k is
+// present.
+func (s *Store[K, V]) Get(k K) (v V, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sl, found := s.slots[k]
+	if !found || !sl.present {
+		return v, false
+	}
+	return sl.b.Get(), true
+}
+
+// Delete removes k. Its watchers stop receiving
+// until k is Set again; they are not ended.
+func (s *Store[K, V]) Delete(k K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sl, ok := s.slots[k]
+	if !ok || !sl.present {
+		return
+	}
+	last := sl.b.Get()
+	sl.present = false
+	sl.b.Clear()
+	s.events.Bcast(Event[K, V]{Type: Deleted, Key: k, Object: last})
+	s.forget(k, sl)
+}
+
+// caller must hold s.mu.
+func (s *Store[K, V]) forget(k K, sl *slot[V]) {
+	if !sl.present && len(sl.watches) == 0 {
+		sl.b.Close()
+		delete(s.slots, k)
+	}
+}
+
+// Watch returns a subscription to k, whose Ch
+// delivers k's value whenever it is present, and
+// each new one as it is Set. k need not be present
+// yet. Release it with Unwatch.
+func (s *Store[K, V]) Watch(k K, opts ...bchan.SubOption) *bchan.SubscriptionOf[V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		panic("kv: Watch called on closed Store")
+	}
+	sl := s.slot(k)
+	sub := sl.b.Subscribe(opts...)
+	sl.watches[sub] = true
+	return sub
+}
+
+// Unwatch ends sub, a subscription from Watch(k),
+// closing its Ch. It is safe to call more than once.
+func (s *Store[K, V]) Unwatch(k K, sub *bchan.SubscriptionOf[V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sl, ok := s.slots[k]
+	if !ok || !sl.watches[sub] {
+		return
+	}
+	sl.b.Unsubscribe(sub)
+	delete(sl.watches, sub)
+	s.forget(k, sl)
+}
+
+// Keys returns the keys present, in no
+// particular order.
+func (s *Store[K, V]) Keys() []K {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]K, 0, len(s.slots))
+	for k, sl := range s.slots {
+		if sl.present {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Len is the number of keys present.
+func (s *Store[K, V]) Len() (n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sl := range s.slots {
+		if sl.present {
+			n++
+		}
+	}
+	return n
+}
+
+// Close closes every key's Bchan, ending all
+// watches. Set and Watch panic afterwards.
+func (s *Store[K, V]) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.events.Close()
+	for k, sl := range s.slots {
+		sl.b.Close()
+		delete(s.slots, k)
+	}
+}
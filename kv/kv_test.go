@@ -0,0 +1,65 @@
+package kv_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan/kv"
+)
+
+func TestStore(t *testing.T) {
+
+	s := kv.New[string, int]()
+	defer s.Close()
+
+	a := s.Watch("a")
+	defer s.Unwatch("a", a)
+	b := s.Watch("b")
+	defer s.Unwatch("b", b)
+
+	s.Set("a", 1)
+	if v := <-a.Ch; v != 1 {
+		t.Fatalf("watcher of a got %d, want 1", v)
+	}
+	select {
+	case v := <-b.Ch:
+		t.Fatalf("watcher of b woken by a Set of a, got %d", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.Set("b", 2)
+	if v := <-b.Ch; v != 2 {
+		t.Fatalf("watcher of b got %d, want 2", v)
+	}
+	if v, ok := s.Get("b"); !ok || v != 2 {
+		t.Fatalf("Get(b) = %d, %v", v, ok)
+	}
+	keys := s.Keys()
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("Keys() = %v", keys)
+	}
+
+	s.Delete("a")
+	if _, ok := s.Get("a"); ok || s.Len() != 1 {
+		t.Fatal("a should be gone")
+	}
+	s.Set("a", 3)
+	if v := <-a.Ch; v != 3 {
+		t.Fatalf("watch should survive Delete; got %d, want 3", v)
+	}
+	s.Unwatch("a", a)
+	s.Unwatch("a", a)
+	closed := make(chan struct{})
+	go func() {
+		for range a.Ch {
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Unwatch should close Ch")
+	}
+}
@@ -0,0 +1,161 @@
+package bchan
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Leak is what CheckLeaks reports. See LeakOf.
+type Leak = LeakOf[interface{}]
+
+// LeakOf reports a value that has been on offer
+// for longer than expected without being taken:
+// the usual sign of a receiver or subscription
+// that was abandoned rather than shut down.
+type LeakOf[T any] struct {
+	// Seq is the sequence number of the value,
+	// deliverable since Since.
+	Seq   uint64
+	Since time.Time
+
+	// ChStalled is true if Ch has stood full,
+	// with nothing taken from it, all that time,
+	// although b has had receivers.
+	ChStalled bool
+
+	// Receivers names the registered receivers
+	// (see RegisterReceiver) that have not taken
+	// Seq, when ChStalled.
+	Receivers []string
+
+	// Subs are the subscriptions that have not
+	// taken Seq; pass them to Unsubscribe if they
+	// are indeed dead.
+	Subs []*SubscriptionOf[T]
+}
+
+func (l LeakOf[T]) String() string {
+	return fmt.Sprintf("bchan: seq=%d untaken since %v: Ch stalled=%v, receivers %q, %d subscriptions",
+		l.Seq, l.Since.Format(time.RFC3339Nano), l.ChStalled, l.Receivers, len(l.Subs))
+}
+
+// leakWatch is what CheckLeaks knows of
+// the value on offer.
+type leakWatch[T any] struct {
+	seq      uint64
+	since    time.Time
+	chSince  time.Time
+	fills    uint64
+	nrecv    map[*SubscriptionOf[T]]uint64
+	reported bool
+}
+
+// CheckLeaks starts a background checker that looks
+// at b every after/2 and calls report, once per value,
+// when a value broadcast for at least after has not
+// been taken: from Ch, which stood full with no
+// receive, while b has registered receivers or has
+// had acks; or by a subscription. Subscriptions with
+// a WithFilter are only judged on values their filter
+// accepts, the filter being called from the checker.
+// report is called without b locked. The checker
+// stops when b is closed, or when stop is called.
+func (b *Of[T]) CheckLeaks(after time.Duration, report func(l LeakOf[T])) (stop func()) {
+	tick := after / 2
+	if tick <= 0 {
+		tick = time.Millisecond
+	}
+	quit := make(chan struct{})
+	wake, unwatch := b.watch()
+	go func() {
+		defer unwatch()
+		var w *leakWatch[T]
+		due, t := b.timeout(tick)
+		defer func() { t.Stop() }()
+		for {
+			select {
+			case <-due:
+				due, t = b.timeout(tick)
+			case _, ok := <-wake:
+				if !ok {
+					return
+				}
+				continue
+			case <-quit:
+				return
+			}
+			var l *LeakOf[T]
+			var cur T
+			w, l, cur = b.checkLeak(w, after, b.now())
+			if l == nil {
+				continue
+			}
+			subs := l.Subs[:0]
+			for _, s := range l.Subs {
+				if s.filter == nil || s.filter(cur) {
+					subs = append(subs, s)
+				}
+			}
+			l.Subs = subs
+			if l.ChStalled || len(l.Subs) > 0 {
+				report(*l)
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(quit) })
+	}
+}
+
+// checkLeak brings w up to date as of now,
+// returning a Leak, and the value it is about,
+// if one may be due; the Subs are yet to be
+// filtered.
+func (b *Of[T]) checkLeak(w *leakWatch[T], after time.Duration, now time.Time) (_ *leakWatch[T], _ *LeakOf[T], cur T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed || !b.on || !b.live || b.paused {
+		return nil, nil, cur
+	}
+	fills := b.nfill.Load()
+	if w == nil || w.seq != b.seq {
+		w = &leakWatch[T]{seq: b.seq, since: now, chSince: now, fills: fills,
+			nrecv: make(map[*SubscriptionOf[T]]uint64)}
+		for s := range b.subs {
+			w.nrecv[s] = s.nrecv.Load()
+		}
+		return w, nil, cur
+	}
+	if fills != w.fills {
+		w.fills, w.chSince = fills, now
+	}
+	if w.reported || now.Sub(w.since) < after {
+		return w, nil, cur
+	}
+	l := &LeakOf[T]{Seq: b.seq, Since: w.since}
+	full := !b.condBackend && len(b.Ch) == cap(b.Ch)
+	if full && now.Sub(w.chSince) >= after && (b.nack.Load() > 0 || len(b.receivers) > 0) {
+		l.ChStalled = true
+		for name, r := range b.receivers {
+			if r.lastSeq < b.seq {
+				l.Receivers = append(l.Receivers, name)
+			}
+		}
+		sort.Strings(l.Receivers)
+	}
+	for s := range b.subs {
+		n, ok := w.nrecv[s]
+		if !ok || n != s.nrecv.Load() || s.lastSeq.Load() >= b.seq {
+			continue
+		}
+		l.Subs = append(l.Subs, s)
+	}
+	if !l.ChStalled && len(l.Subs) == 0 {
+		return w, nil, cur
+	}
+	w.reported = true
+	return w, l, b.cur
+}
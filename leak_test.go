@@ -0,0 +1,78 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestCheckLeaksAbandonedSubscription(t *testing.T) {
+
+	bc := bchan.New(1)
+	defer bc.Close()
+	live := bc.Subscribe()
+	dead := bc.Subscribe()
+	leaks := make(chan bchan.Leak, 10)
+	stop := bc.CheckLeaks(20*time.Millisecond, func(l bchan.Leak) { leaks <- l })
+	defer stop()
+
+	bc.Bcast("bill")
+	<-live.Ch
+	select {
+	case l := <-leaks:
+		if l.Seq != 1 || l.ChStalled {
+			t.Fatalf("unexpected leak %v", l)
+		}
+		if len(l.Subs) != 1 || l.Subs[0] != dead {
+			t.Fatalf("expected just the unread subscription, got %v", l.Subs)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("abandoned subscription not reported")
+	}
+	select {
+	case l := <-leaks:
+		t.Fatalf("leak reported twice for one value: %v", l)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCheckLeaksStalledCh(t *testing.T) {
+
+	bc := bchan.New(1)
+	defer bc.Close()
+	bc.RegisterReceiver("worker")
+	leaks := make(chan bchan.Leak, 10)
+	stop := bc.CheckLeaks(20*time.Millisecond, func(l bchan.Leak) { leaks <- l })
+	defer stop()
+
+	bc.Bcast("bill")
+	select {
+	case l := <-leaks:
+		if !l.ChStalled || len(l.Receivers) != 1 || l.Receivers[0] != "worker" {
+			t.Fatalf("unexpected leak %v", l)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stalled Ch not reported")
+	}
+}
+
+func TestCheckLeaksQuiet(t *testing.T) {
+
+	bc := bchan.New(1)
+	leaks := make(chan bchan.Leak, 10)
+	stop := bc.CheckLeaks(10*time.Millisecond, func(l bchan.Leak) { leaks <- l })
+
+	// no receivers: a full Ch is no leak.
+	bc.Bcast("bill")
+	time.Sleep(50 * time.Millisecond)
+	bc.Off()
+	select {
+	case l := <-leaks:
+		t.Fatalf("unexpected leak %v", l)
+	default:
+	}
+	stop()
+	stop()
+	bc.Close()
+}
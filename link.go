@@ -0,0 +1,160 @@
+package bchan
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// AddChild makes child follow b: every value
+// broadcast (or Set) on b is broadcast (or Set) on
+// child too, and turning b on or off does the same
+// to child. Children can have children of their
+// own, so Bchans can be arranged as a tree for
+// scoped distribution such as global, then region,
+// then node. The copying is done by a goroutine
+// owned by the package, which stops when unlink is
+// called or when either Bchan is closed. Under a
+// burst of updates a child may skip straight to the
+// latest value.
+func (b *Of[T]) AddChild(child *Of[T]) (unlink func()) {
+	return Link(b, child, nil)
+}
+
+// Link is AddChild for a child of another type, or
+// one that should see a transformed value: child
+// gets fn(v) for every v broadcast on parent. fn may
+// be nil only if a P can be assigned to a C; Link
+// panics otherwise.
+func Link[P, C any](parent *Of[P], child *Of[C], fn func(v P) C) (unlink func()) {
+	if fn == nil {
+		if p, c := reflect.TypeFor[P](), reflect.TypeFor[C](); !p.AssignableTo(c) {
+			panic(fmt.Sprintf("bchan: Link needs a fn to turn parent %v values into child %v values", p, c))
+		}
+		fn = func(v P) C {
+			c, _ := interface{}(v).(C) // fails only for nil, giving nil.
+			return c
+		}
+	}
+	wake, cancel := parent.watch()
+	childWake, childCancel := child.watch()
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer cancel()
+		defer childCancel()
+		var last followState[P]
+		for {
+			cur := parent.followState()
+			if !followInto(child, last, cur, fn) {
+				return
+			}
+			last = cur
+			select {
+			case _, ok := <-wake:
+				if !ok {
+					return
+				}
+			case _, ok := <-childWake:
+				if !ok {
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+		})
+	}
+}
+
+// followState is what a follower copies.
+// out is b.out at the time, for handout.
+type followState[T any] struct {
+	val  T
+	seq  uint64
+	on   bool
+	live bool
+	out  func(v T) T
+}
+
+func (b *Of[T]) followState() followState[T] {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return followState[T]{val: b.cur, seq: b.seq, on: b.on, live: b.live, out: b.out}
+}
+
+// handout returns val as a receiver
+// would be given it.
+func (st followState[T]) handout() T {
+	if st.out == nil {
+		return st.val
+	}
+	return st.out(st.val)
+}
+
+// followInto applies changes between last and cur to
+// child, reporting false if child has been closed.
+func followInto[P, C any](child *Of[C], last, cur followState[P], fn func(P) C) bool {
+	if cur.seq == last.seq && cur.on == last.on && cur.live == last.live {
+		return true
+	}
+	var v C
+	changed := cur.seq != last.seq && cur.seq != 0
+	if changed {
+		// run fn before locking the child.
+		v = fn(cur.handout())
+	}
+	child.lockForBcast()
+	defer child.mu.Unlock()
+	if child.closed {
+		return false
+	}
+	switch {
+	case changed && cur.on && cur.live:
+		child.bcast("Link", v)
+	case changed:
+		if child.admit("Link", &v) != nil {
+			break
+		}
+		child.cancelPending()
+		child.store("Link", v)
+		child.drain()
+		child.publish(false)
+	}
+	switch {
+	case cur.on && cur.live && !changed && !(child.on && child.live):
+		child.turnOn()
+	case !cur.on && child.on:
+		child.off()
+	}
+	return true
+}
+
+// Tee makes every one of dsts follow src, as
+// AddChild does, so that one producer's updates
+// can be split across subsystems whose consumer
+// populations, and so diameters, differ. stop
+// unlinks them all.
+func Tee(src *Bchan, dsts ...*Bchan) (stop func()) {
+	return TeeOf(src, dsts...)
+}
+
+// TeeOf is Tee for Bchans of any one type T.
+func TeeOf[T any](src *Of[T], dsts ...*Of[T]) (stop func()) {
+	unlinks := make([]func(), len(dsts))
+	for i, d := range dsts {
+		unlinks[i] = src.AddChild(d)
+	}
+	return func() {
+		for _, u := range unlinks {
+			u()
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package bchan_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/bchantest"
+)
+
+// eventually polls cond for up to a second.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for " + what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAddChild(t *testing.T) {
+
+	global := bchan.NewOf[string](1)
+	region := bchan.NewOf[string](1)
+	node := bchan.NewOf[string](1)
+	unlink := global.AddChild(region)
+	defer unlink()
+	shout := bchan.Link(region, node, strings.ToUpper)
+	defer shout()
+
+	global.Bcast("debug=off")
+	eventually(t, "node to follow global", func() bool {
+		v, ok := node.Cur()
+		return ok && v == "DEBUG=OFF"
+	})
+
+	global.Off()
+	eventually(t, "node to follow Off", func() bool { return !node.IsOn() })
+
+	unlink()
+	global.Bcast("debug=on")
+	time.Sleep(20 * time.Millisecond)
+	if v := region.Get(); v != "debug=off" {
+		t.Fatalf("unlinked child should not follow, got %v", v)
+	}
+}
+
+func TestTee(t *testing.T) {
+
+	src := bchan.New(1)
+	small, big := bchan.New(1), bchan.New(16)
+	stop := bchan.Tee(src, small, big)
+	defer stop()
+
+	src.Bcast("v1")
+	for _, d := range []*bchan.Bchan{small, big} {
+		eventually(t, "a tee destination", func() bool {
+			v, ok := d.Cur()
+			return ok && v == "v1"
+		})
+	}
+	if n := len(big.Ch); n != cap(big.Ch) {
+		t.Fatalf("the big destination holds %d copies, want %d", n, cap(big.Ch))
+	}
+}
+
+func TestLinkNilFn(t *testing.T) {
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Link of int to string with no fn should panic at once")
+		}
+	}()
+	bchan.Link[int, string](bchan.NewOf[int](1), bchan.NewOf[string](1), nil)
+}
+
+func TestLinkChildClosed(t *testing.T) {
+
+	parent := bchan.NewOf[int](1)
+	defer parent.Close()
+	check := bchantest.CheckGoroutines(t)
+	child := bchan.NewOf[int](1)
+	parent.AddChild(child)
+	parent.Bcast(1)
+	eventually(t, "the child to follow", func() bool { return child.Get() == 1 })
+
+	// closing the child ends the link, with
+	// nothing broadcast on the parent.
+	child.Close()
+	check()
+}
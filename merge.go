@@ -0,0 +1,76 @@
+package bchan
+
+import (
+	"context"
+	"sync"
+)
+
+// Tagged is a merged value; see TaggedOf.
+type Tagged = TaggedOf[interface{}]
+
+// TaggedOf is a value forwarded by Merge, marked
+// with where it came from: From is the source
+// Bchan, and Index its position among Merge's ins.
+type TaggedOf[T any] struct {
+	Index int
+	From  *Of[T]
+	Val   T
+}
+
+// Merge forwards the current value of each of ins
+// that is broadcasting, and then every new value
+// broadcast on any of them, to out as a Tagged, so
+// consumers can watch one Bchan instead of len(ins).
+// Only values are forwarded; a source turning off
+// leaves out alone.
+// Each source is followed by a goroutine owned by the
+// package, which under a burst of updates may skip
+// to the source's latest value; the goroutines stop
+// when stop is called, or for each source when it or
+// out is closed.
+func Merge(out *Bchan, ins ...*Bchan) (stop func()) {
+	return merge(out, ins, func(i int, v interface{}) interface{} {
+		return Tagged{Index: i, From: ins[i], Val: v}
+	})
+}
+
+// MergeOf is Merge for sources of one type T, whose
+// destination carries TaggedOf[T] values.
+func MergeOf[T any](out *Of[TaggedOf[T]], ins ...*Of[T]) (stop func()) {
+	return merge(out, ins, func(i int, v T) TaggedOf[T] {
+		return TaggedOf[T]{Index: i, From: ins[i], Val: v}
+	})
+}
+
+func merge[T, O any](out *Of[O], ins []*Of[T], tag func(i int, v T) O) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i, in := range ins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var seen uint64
+			in.Follow(ctx, func(v T, seq uint64, on bool) error {
+				if !on || seq == seen {
+					return nil
+				}
+				seen = seq
+				o := tag(i, v)
+				out.lockForBcast()
+				defer out.mu.Unlock()
+				if out.closed {
+					return ErrClosed
+				}
+				out.bcast("Merge", o)
+				return nil
+			})
+		}()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}
+}
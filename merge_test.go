@@ -0,0 +1,48 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestMerge(t *testing.T) {
+
+	a, b := bchan.New(1), bchan.New(1)
+	a.Bcast("a0")
+	out := bchan.New(1)
+	stop := bchan.Merge(out, a, b)
+	defer stop()
+
+	next := func() bchan.Tagged {
+		t.Helper()
+		select {
+		case v := <-out.Ch:
+			out.BcastAck()
+			return v.(bchan.Tagged)
+		case <-time.After(5 * time.Second):
+			t.Fatal("nothing merged")
+		}
+		panic("unreachable")
+	}
+	if m := next(); m.Index != 0 || m.From != a || m.Val != "a0" {
+		t.Fatalf("got %+v, want a's current value", m)
+	}
+	b.Bcast("b1")
+	eventually(t, "b's value", func() bool {
+		m, ok := out.Get().(bchan.Tagged)
+		return ok && m.Index == 1 && m.Val == "b1"
+	})
+}
+
+func TestMergeOf(t *testing.T) {
+
+	a := bchan.NewOf[int](1)
+	out := bchan.NewOf[bchan.TaggedOf[int]](1)
+	stop := bchan.MergeOf(out, a)
+	a.Bcast(42)
+	eventually(t, "the merged value", func() bool { return out.Get().Val == 42 })
+	stop()
+	stop()
+}
@@ -0,0 +1,127 @@
+// package metrics exports bchan counters to Prometheus.
+//
+// A Collector watches any number of Bchans, each under
+// its own name, and reports ack and broadcast counts,
+// channel occupancy, and a histogram of BcastAck refill
+// latency, so that stalled fan-out can be alerted on.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Source is what a Collector needs from a Bchan.
+// Every bchan.Of[T] satisfies it, whatever T is.
+type Source interface {
+	Stats() bchan.Stats
+	ObserveAcks(f func(d time.Duration))
+}
+
+// Collector is a prometheus.Collector over
+// a set of named Bchans.
+type Collector struct {
+	mu   sync.Mutex
+	srcs map[string]Source
+
+	acks    *prometheus.Desc
+	bcasts  *prometheus.Desc
+	fills   *prometheus.Desc
+	drains  *prometheus.Desc
+	length  *prometheus.Desc
+	capac   *prometheus.Desc
+	on      *prometheus.Desc
+	latency *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = &Collector{}
+
+// NewCollector makes an empty Collector whose metric
+// names start with namespace (for example "myapp"
+// gives "myapp_bchan_acks_total"). Register it
+// with prometheus, then Add the Bchans to watch.
+func NewCollector(namespace string) *Collector {
+	label := []string{"bchan"}
+	name := func(s string) string {
+		return prometheus.BuildFQName(namespace, "bchan", s)
+	}
+	return &Collector{
+		srcs:   make(map[string]Source),
+		acks:   prometheus.NewDesc(name("acks_total"), "BcastAck calls.", label, nil),
+		bcasts: prometheus.NewDesc(name("broadcasts_total"), "Set and Bcast calls.", label, nil),
+		fills:  prometheus.NewDesc(name("fills_total"), "Values placed into the channel.", label, nil),
+		drains: prometheus.NewDesc(name("drains_total"), "Stale values drained from the channel.", label, nil),
+		length: prometheus.NewDesc(name("occupancy"), "Values currently queued in the channel.", label, nil),
+		capac:  prometheus.NewDesc(name("capacity"), "Capacity of the channel.", label, nil),
+		on:     prometheus.NewDesc(name("on"), "1 if broadcasting is on.", label, nil),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "bchan",
+			Name:      "refill_latency_seconds",
+			Help:      "Time taken by BcastAck, including lock wait.",
+			Buckets:   prometheus.ExponentialBuckets(1e-7, 4, 10),
+		}, label),
+	}
+}
+
+// Add starts collecting from src under name,
+// replacing any Source already using that name.
+func (c *Collector) Add(name string, src Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.srcs[name]; ok {
+		old.ObserveAcks(nil)
+	}
+	c.srcs[name] = src
+	h := c.latency.WithLabelValues(name)
+	src.ObserveAcks(func(d time.Duration) {
+		h.Observe(d.Seconds())
+	})
+}
+
+// Remove stops collecting the Source called name.
+func (c *Collector) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if src, ok := c.srcs[name]; ok {
+		src.ObserveAcks(nil)
+		delete(c.srcs, name)
+		c.latency.DeleteLabelValues(name)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acks
+	ch <- c.bcasts
+	ch <- c.fills
+	ch <- c.drains
+	ch <- c.length
+	ch <- c.capac
+	ch <- c.on
+	c.latency.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, src := range c.srcs {
+		st := src.Stats()
+		on := 0.0
+		if st.On {
+			on = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.acks, prometheus.CounterValue, float64(st.Acks), name)
+		ch <- prometheus.MustNewConstMetric(c.bcasts, prometheus.CounterValue, float64(st.Seq), name)
+		ch <- prometheus.MustNewConstMetric(c.fills, prometheus.CounterValue, float64(st.Fills), name)
+		ch <- prometheus.MustNewConstMetric(c.drains, prometheus.CounterValue, float64(st.Drains), name)
+		ch <- prometheus.MustNewConstMetric(c.length, prometheus.GaugeValue, float64(st.Len), name)
+		ch <- prometheus.MustNewConstMetric(c.capac, prometheus.GaugeValue, float64(st.Cap), name)
+		ch <- prometheus.MustNewConstMetric(c.on, prometheus.GaugeValue, on, name)
+	}
+	c.latency.Collect(ch)
+}
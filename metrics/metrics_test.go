@@ -0,0 +1,49 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+
+	bc := bchan.New(2)
+	typed := bchan.NewOf[int](1)
+
+	c := metrics.NewCollector("test")
+	c.Add("config", bc)
+	c.Add("epoch", typed)
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+
+	bc.Bcast("bill")
+	<-bc.Ch
+	bc.BcastAck()
+	typed.Bcast(1)
+
+	want := `
+# HELP test_bchan_acks_total BcastAck calls.
+# TYPE test_bchan_acks_total counter
+test_bchan_acks_total{bchan="config"} 1
+test_bchan_acks_total{bchan="epoch"} 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "test_bchan_acks_total"); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(c, "test_bchan_refill_latency_seconds"); n != 2 {
+		t.Fatalf("expected a latency histogram series per Bchan, got %v", n)
+	}
+
+	c.Remove("epoch")
+	if n := testutil.CollectAndCount(c, "test_bchan_on"); n != 1 {
+		t.Fatalf("expected one series after Remove, got %v", n)
+	}
+	if n := testutil.CollectAndCount(c, "test_bchan_refill_latency_seconds"); n != 1 {
+		t.Fatalf("expected one latency series after Remove, got %v", n)
+	}
+}
@@ -0,0 +1,176 @@
+// package netbridge replicates a Bchan to mirror
+// Bchans in other processes over plain TCP.
+//
+// The source side calls Listen; each mirror calls
+// Dial, which keeps reconnecting until it is closed,
+// so a mirror rides out source restarts on its own.
+// Every connection carries a stream of frames, each
+// the source's state after a change, in a pluggable
+// bchan.Codec such as bchan.GobCodec or bchan.JSONCodec.
+package netbridge
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+// frame is the source's state after a change.
+type frame[T any] struct {
+	Seq uint64
+	On  bool
+	Val T
+}
+
+// Listener serves one source Bchan to mirrors.
+type Listener struct {
+	ln     net.Listener
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Listen accepts mirror connections on addr and
+// streams src to each of them: its current state on
+// connect, then every change.
+func Listen[T any](addr string, src *bchan.Of[T], c bchan.Codec) (*Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	l := &Listener{ln: ln}
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			l.wg.Add(1)
+			go func() {
+				defer l.wg.Done()
+				serve(l.ctx, conn, src, c)
+			}()
+		}
+	}()
+	return l, nil
+}
+
+// Addr is the address l is listening on.
+func (l *Listener) Addr() net.Addr {
+	return l.ln.Addr()
+}
+
+// Close stops accepting, drops every connected
+// mirror, and waits for their goroutines to finish.
+func (l *Listener) Close() error {
+	err := l.ln.Close()
+	l.cancel()
+	l.wg.Wait()
+	return err
+}
+
+func serve[T any](ctx context.Context, conn net.Conn, src *bchan.Of[T], c bchan.Codec) {
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		// mirrors send nothing; a read returns
+		// once the connection is gone.
+		io.Copy(io.Discard, conn)
+		cancel()
+	}()
+	context.AfterFunc(ctx, func() { conn.Close() })
+	e := c.NewEncoder(conn)
+	src.Follow(ctx, func(v T, seq uint64, on bool) error {
+		return e.Encode(&frame[T]{Seq: seq, On: on, Val: v})
+	})
+}
+
+// Mirror keeps a local Bchan in step with a
+// remote source; see Dial.
+type Mirror struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Retry bounds for Dial's reconnect backoff.
+const (
+	minRetry = 50 * time.Millisecond
+	maxRetry = 5 * time.Second
+)
+
+// Dial connects to the Listener at addr and makes
+// into follow its source: each new value is Bcast
+// on into (or just Set, if the source is off), and
+// the source turning on or off does the same to
+// into. Whenever the connection fails or cannot be
+// made, Dial's goroutine retries with backoff until
+// Close; meanwhile into keeps its last value. If
+// into is closed, the mirror stops at the next frame,
+// as if Closed.
+func Dial[T any](addr string, into *bchan.Of[T], c bchan.Codec) *Mirror {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Mirror{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(m.done)
+		wait := minRetry
+		var d net.Dialer
+		for {
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err == nil {
+				got, open := mirror(ctx, conn, into, c)
+				if !open {
+					return
+				}
+				if got {
+					wait = minRetry
+				}
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+			if wait *= 2; wait > maxRetry {
+				wait = maxRetry
+			}
+		}
+	}()
+	return m
+}
+
+// Close stops m and disconnects it.
+func (m *Mirror) Close() {
+	m.cancel()
+	<-m.done
+}
+
+// mirror applies frames from conn to into until
+// the connection ends or into is closed, reporting
+// whether any arrived, and whether into is open.
+func mirror[T any](ctx context.Context, conn net.Conn, into *bchan.Of[T], c bchan.Codec) (got, open bool) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	d := c.NewDecoder(conn)
+	var seq uint64
+	for {
+		var f frame[T]
+		if err := d.Decode(&f); err != nil {
+			return got, true
+		}
+		changed := !got || f.Seq != seq
+		if into.ApplyState(f.Val, changed, f.On) == bchan.ErrClosed {
+			return got, false
+		}
+		got = true
+		seq = f.Seq
+	}
+}
@@ -0,0 +1,102 @@
+package netbridge_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/bchantest"
+	"github.com/glycerine/bchan/netbridge"
+)
+
+// want waits for b to be broadcasting v.
+func want[T comparable](t *testing.T, b *bchan.Of[T], v T) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if got, ok := b.Cur(); ok && got == v {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("mirror never got %v", v)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMirror(t *testing.T) {
+
+	for _, c := range []bchan.Codec{bchan.GobCodec, bchan.JSONCodec} {
+		src := bchan.NewOf[string](1)
+		src.Bcast("v1")
+		l, err := netbridge.Listen("127.0.0.1:0", src, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dst := bchan.NewOf[string](1)
+		m := netbridge.Dial(l.Addr().String(), dst, c)
+		want(t, dst, "v1")
+		src.Bcast("v2")
+		want(t, dst, "v2")
+		src.Off()
+		eventually := time.Now().Add(5 * time.Second)
+		for dst.IsOn() {
+			if time.Now().After(eventually) {
+				t.Fatal("mirror never followed Off")
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		m.Close()
+		l.Close()
+	}
+}
+
+func TestReconnect(t *testing.T) {
+
+	src := bchan.NewOf[int](1)
+	src.Bcast(1)
+	l, err := netbridge.Listen("127.0.0.1:0", src, bchan.GobCodec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+
+	dst := bchan.NewOf[int](1)
+	m := netbridge.Dial(addr, dst, bchan.GobCodec)
+	defer m.Close()
+	want(t, dst, 1)
+
+	// restart the source side.
+	l.Close()
+	src.Bcast(2)
+	if l, err = netbridge.Listen(addr, src, bchan.GobCodec); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	want(t, dst, 2)
+}
+
+func TestMirrorIntoClosed(t *testing.T) {
+
+	src := bchan.NewOf[int](1)
+	src.Bcast(1)
+	l, err := netbridge.Listen("127.0.0.1:0", src, bchan.GobCodec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	check := bchantest.CheckGoroutines(t)
+	dst := bchan.NewOf[int](1)
+	m := netbridge.Dial(l.Addr().String(), dst, bchan.GobCodec)
+	defer m.Close()
+	want(t, dst, 1)
+
+	// the next frame finds dst closed: the mirror
+	// must stop, not panic.
+	dst.Close()
+	src.Bcast(2)
+	check()
+}
@@ -0,0 +1,85 @@
+package bchan
+
+import (
+	"context"
+)
+
+// Observer is notified of new values on a Bchan.
+// See ObserverOf.
+type Observer = ObserverOf[interface{}]
+
+// ObserverOf is implemented by anything that wants
+// to be told of each new value broadcast on an
+// Of[T], along with its sequence number (see Seq).
+type ObserverOf[T any] interface {
+	Notify(val T, seq uint64)
+}
+
+// AddObserver registers o to be notified of each
+// value broadcast on b from now on. All of b's
+// observers are notified by one dispatcher goroutine,
+// in the order they were added, so every observer
+// sees the same values in the same order, and sees
+// each value only after the observers added before
+// it have been told of it. If one Notify panics, the
+// panic is recovered and logged (see WithLogger), and
+// the rest are still notified. If the dispatcher falls
+// behind, observers skip to the latest value together.
+// Call remove to stop notifying o; it does not wait
+// for a Notify already in progress.
+func (b *Of[T]) AddObserver(o ObserverOf[T]) (remove func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := &observer[T]{o: o}
+	b.observers = append(b.observers, e)
+	if !b.dispatching && !b.closed {
+		b.dispatching = true
+		go b.dispatch(b.seq)
+	}
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, x := range b.observers {
+			if x == e {
+				b.observers = append(b.observers[:i:i], b.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// observer wraps an ObserverOf so that the same
+// value can be added twice and removed by handle.
+type observer[T any] struct {
+	o ObserverOf[T]
+}
+
+// dispatch notifies b's observers until b is
+// closed, starting after seq.
+func (b *Of[T]) dispatch(seen uint64) {
+	b.labelGoroutine()
+	b.Follow(context.Background(), func(v T, seq uint64, on bool) error {
+		if !on || seq == seen {
+			return nil
+		}
+		seen = seq
+		b.mu.Lock()
+		obs := b.observers
+		b.mu.Unlock()
+		for _, e := range obs {
+			b.notify(e.o, v, seq)
+		}
+		return nil
+	})
+}
+
+func (b *Of[T]) notify(o ObserverOf[T], v T, seq uint64) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.logf("bchan: observer %T panicked on seq=%d: %v", o, seq, r)
+		}
+	}()
+	o.Notify(v, seq)
+}
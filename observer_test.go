@@ -0,0 +1,76 @@
+package bchan_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+type recorder struct {
+	name string
+	mu   *sync.Mutex
+	log  *[]string
+}
+
+func (r recorder) Notify(v int, seq uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*r.log = append(*r.log, fmt.Sprintf("%s:%d@%d", r.name, v, seq))
+}
+
+type panicker struct{}
+
+func (panicker) Notify(v int, seq uint64) { panic("boom") }
+
+func TestAddObserver(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	var mu sync.Mutex
+	var log []string
+	bc.AddObserver(recorder{"a", &mu, &log})
+	bc.AddObserver(panicker{})
+	remove := bc.AddObserver(recorder{"b", &mu, &log})
+
+	bc.Bcast(7)
+	n := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(log)
+	}
+	eventually(t, "both observers", func() bool { return n() == 2 })
+	remove()
+	bc.Bcast(8)
+	eventually(t, "the remaining observer", func() bool { return n() == 3 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"a:7@1", "b:7@1", "a:8@2"}
+	for i := range want {
+		if log[i] != want[i] {
+			t.Fatalf("got %v, want %v", log, want)
+		}
+	}
+}
+
+func TestAddObserverHandedOut(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.InterceptDeliver(func(v int, next func(int) int) int { return next(v) + 100 })
+	var mu sync.Mutex
+	var log []string
+	bc.AddObserver(recorder{"a", &mu, &log})
+
+	bc.Bcast(1)
+	eventually(t, "the observer", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(log) == 1
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if log[0] != "a:101@1" {
+		t.Fatalf("got %v, want a:101@1 from the delivery interceptor", log)
+	}
+}
@@ -0,0 +1,45 @@
+package bchan
+
+// WithAtMostOnce makes a Bchan whose values are not
+// sticky: each Set or Bcast puts the value into Ch
+// (and each consumer group's channel) for as many
+// receivers as the diameter, once, and a receive
+// takes one copy for good. BcastAck refills
+// nothing, and neither do On, Resume or a heartbeat
+// for a value already handed out, so once the
+// copies are taken receives block until the next
+// broadcast. Use it to announce work, where
+// handing a stale value out again would do harm.
+// Receivers should still call BcastAck, which
+// keeps the counts of Stats and BcastAndWait.
+// Subscriptions are not affected.
+func WithAtMostOnce() Option {
+	return func(c *config) {
+		c.atMostOnce = true
+	}
+}
+
+// fillOnce is fillCh in at-most-once mode: it
+// puts diameter copies of the current value in
+// ch, if it has not done so already for this
+// value.
+// Caller must hold b.mu.
+func (b *Of[T]) fillOnce(ch chan T) {
+	if b.onceFilled[ch] == b.seq {
+		return
+	}
+	b.onceFilled[ch] = b.seq
+	for n := cap(ch) - 1 - len(ch); n > 0; n-- {
+		v := b.handout(b.cur)
+		select {
+		case ch <- v:
+			b.nfill.Add(1)
+			if ch == b.Ch {
+				b.seqFills++
+				b.chFills++
+			}
+		default:
+			return
+		}
+	}
+}
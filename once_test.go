@@ -0,0 +1,46 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestAtMostOnce(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[string](bchan.WithAtMostOnce(), bchan.WithDiameter(3))
+	bc.Bcast("job1")
+	for i := 0; i < 3; i++ {
+		select {
+		case v := <-bc.Ch:
+			if v != "job1" {
+				t.Fatalf("got %q, want job1", v)
+			}
+			bc.BcastAck()
+		default:
+			t.Fatalf("receive %d found nothing; want 3 copies", i)
+		}
+	}
+	none := func(when string) {
+		t.Helper()
+		select {
+		case v := <-bc.Ch:
+			t.Fatalf("%s: got %q again", when, v)
+		default:
+		}
+	}
+	none("after 3 receives")
+
+	bc.Off()
+	bc.On()
+	none("after Off and On")
+
+	bc.Bcast("job2")
+	if v := <-bc.Ch; v != "job2" {
+		t.Fatalf("got %q, want job2", v)
+	}
+	bc.BcastAck()
+	if n := len(bc.Ch); n != 2 {
+		t.Fatalf("%d copies of job2 left, want 2", n)
+	}
+}
@@ -0,0 +1,44 @@
+package bchan
+
+import (
+	"context"
+	"sync"
+)
+
+// OnChange calls fn with each new value broadcast on
+// b from now on, for consumers that would rather be
+// called back than run their own receive and ack
+// loop. The calls come one at a time from a
+// dispatcher goroutine owned by the package; like a
+// linked child (see AddChild), it skips straight to
+// the latest value if fn falls behind. A value Set
+// while broadcasting is off is passed to fn once it
+// is turned on. Calling cancel stops the dispatcher
+// and waits for any call in progress to return, so
+// it must not be called from fn itself. Closing b
+// stops the dispatcher too.
+func (b *Of[T]) OnChange(fn func(v T)) (cancel func()) {
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	b.mu.Lock()
+	seen := b.seq
+	b.mu.Unlock()
+	go func() {
+		defer close(done)
+		b.labelGoroutine()
+		b.Follow(ctx, func(v T, seq uint64, on bool) error {
+			if on && seq != seen {
+				seen = seq
+				fn(v)
+			}
+			return nil
+		})
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			stop()
+			<-done
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package bchan_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestOnChange(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(0) // before registering; not reported
+
+	var mu sync.Mutex
+	var got []int
+	last := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(got) == 0 {
+			return -1
+		}
+		return got[len(got)-1]
+	}
+	cancel := bc.OnChange(func(v int) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, v)
+	})
+
+	bc.Bcast(1)
+	eventually(t, "the callback", func() bool { return last() == 1 })
+	bc.Set(2)
+	bc.On()
+	eventually(t, "the callback after On", func() bool { return last() == 2 })
+
+	cancel()
+	cancel()
+	bc.Bcast(3)
+	mu.Lock()
+	defer mu.Unlock()
+	for _, v := range got {
+		if v == 0 || v == 3 {
+			t.Fatalf("callback saw %d, from outside its registration: %v", v, got)
+		}
+	}
+}
+
+func TestOnChangeHandedOut(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.InterceptDeliver(func(v int, next func(int) int) int { return next(v) + 100 })
+	got := make(chan int, 1)
+	cancel := bc.OnChange(func(v int) { got <- v })
+	defer cancel()
+
+	bc.Bcast(1)
+	if v := <-got; v != 101 {
+		t.Fatalf("OnChange got %d, want 101 from the delivery interceptor", v)
+	}
+}
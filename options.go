@@ -0,0 +1,205 @@
+package bchan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"time"
+)
+
+// Option configures a Bchan made by NewWithOptions.
+type Option func(c *config)
+
+type config struct {
+	diameter   int
+	initial    interface{}
+	hasInitial bool
+	on         bool
+	history    int
+	logger     Logger
+	debounce   time.Duration
+	equal      interface{}
+	idle       time.Duration
+	journal    io.Writer
+	codec      Codec
+	clone      interface{}
+	validator  interface{}
+	reducer    interface{}
+	cond       bool
+	atMostOnce bool
+	profName   string
+	clock      Clock
+	stepFills  bool
+	invReport  func(err error)
+}
+
+// Logger is the logging interface used by
+// WithLogger; *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithDiameter sets the expectedDiameter; see New.
+// The default is 1.
+func WithDiameter(expectedDiameter int) Option {
+	return func(c *config) {
+		c.diameter = expectedDiameter
+	}
+}
+
+// WithInitial sets the starting value. With a
+// typed Of[T], val must be a T.
+func WithInitial(val interface{}) Option {
+	return func(c *config) {
+		c.initial = val
+		c.hasInitial = true
+	}
+}
+
+// WithOn starts the Bchan already broadcasting.
+func WithOn() Option {
+	return func(c *config) {
+		c.on = true
+	}
+}
+
+// WithHistory is KeepHistory(n) from the start.
+func WithHistory(n int) Option {
+	return func(c *config) {
+		c.history = n
+	}
+}
+
+// WithLogger reports state changes (On, Set,
+// Bcast, Clear, Close) to l. See also SetLogger.
+func WithLogger(l Logger) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// WithDebounce is SetDebounce(d) from the start.
+func WithDebounce(d time.Duration) Option {
+	return func(c *config) {
+		c.debounce = d
+	}
+}
+
+// WithEqual is SetEqual(eq) from the start.
+// eq must be a func(a, b T) bool for the T of
+// the Bchan being made.
+func WithEqual[T any](eq func(a, b T) bool) Option {
+	return func(c *config) {
+		c.equal = eq
+	}
+}
+
+// WithIdleTimeout is SetIdleTimeout(d) from the start.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.idle = d
+	}
+}
+
+// WithCodec is SetCodec(c) from the start.
+func WithCodec(codec Codec) Option {
+	return func(c *config) {
+		c.codec = codec
+	}
+}
+
+// WithJournal is Journal(w) from the start, so
+// that any WithInitial value is recorded too.
+func WithJournal(w io.Writer) Option {
+	return func(c *config) {
+		c.journal = w
+	}
+}
+
+// WithCondBackend makes a Bchan for very wide
+// fan-out. Its receivers wait with RecvAfter on a
+// sync.Cond and a sequence number instead of on a
+// buffered Ch, so no channel slot per receiver is
+// allocated, drained or refilled on each update,
+// and WithDiameter is ignored. Ch is left
+// unbuffered and is never sent on, and BcastAck
+// is not needed.
+func WithCondBackend() Option {
+	return func(c *config) {
+		c.cond = true
+	}
+}
+
+// NewWithOptions makes a Bchan configured by opts.
+// New options can be added here over time without
+// changing the signature of New.
+func NewWithOptions(opts ...Option) *Bchan {
+	return NewOfWithOptions[interface{}](opts...)
+}
+
+// NewOfWithOptions is the type-parameterized NewWithOptions.
+func NewOfWithOptions[T any](opts ...Option) *Of[T] {
+	var cfg config
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.cond {
+		cfg.diameter = 1
+	}
+	b := NewOf[T](cfg.diameter)
+	if cfg.cond {
+		b.setCh(make(chan T))
+		b.condBackend = true
+	}
+	b.clock = cfg.clock
+	b.stepFills = cfg.stepFills
+	b.invReport = cfg.invReport
+	b.logger = cfg.logger
+	b.codec = cfg.codec
+	b.equal = optFunc[func(a, b T) bool](cfg.equal, "WithEqual")
+	b.clone = optFunc[func(v T) T](cfg.clone, "WithClone")
+	b.rebuildOut()
+	b.validator = optFunc[func(v T) error](cfg.validator, "WithValidator")
+	b.reducer = optFunc[func(cur, incoming T) T](cfg.reducer, "WithReducer")
+	if cfg.atMostOnce {
+		b.onceFilled = make(map[chan T]uint64)
+	}
+	if cfg.profName != "" {
+		b.profCtx = pprof.WithLabels(context.Background(), pprof.Labels("bchan", cfg.profName))
+	}
+	if cfg.history > 0 {
+		b.KeepHistory(cfg.history)
+	}
+	if cfg.journal != nil {
+		b.Journal(cfg.journal)
+	}
+	if cfg.hasInitial {
+		var val T
+		if cfg.initial != nil {
+			v, ok := cfg.initial.(T)
+			if !ok {
+				panic(fmt.Sprintf("bchan: WithInitial value of type %T is not a %T", cfg.initial, val))
+			}
+			val = v
+		}
+		b.Set(val)
+	}
+	if cfg.on {
+		b.On()
+	}
+	if cfg.debounce > 0 {
+		b.SetDebounce(cfg.debounce)
+	}
+	if cfg.idle > 0 {
+		b.SetIdleTimeout(cfg.idle)
+	}
+	return b
+}
+
+// logf logs to b's Logger, if it has one.
+func (b *Of[T]) logf(format string, v ...interface{}) {
+	if b.logger != nil {
+		b.logger.Printf(format, v...)
+	}
+}
@@ -0,0 +1,46 @@
+package bchan_test
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestNewWithOptions(t *testing.T) {
+
+	var buf bytes.Buffer
+	bc := bchan.NewOfWithOptions[string](
+		bchan.WithDiameter(4),
+		bchan.WithInitial("bill"),
+		bchan.WithOn(),
+		bchan.WithHistory(2),
+		bchan.WithLogger(log.New(&buf, "", 0)),
+	)
+	if cap(bc.Ch) != 5 {
+		t.Fatalf("expected cap 5, got %v", cap(bc.Ch))
+	}
+	if v, ok := bc.Cur(); !ok || v != "bill" {
+		t.Fatalf("expected bill, true; got %v, %v", v, ok)
+	}
+	if v := <-bc.Ch; v != "bill" {
+		t.Fatalf("expected to start on with bill, got %v", v)
+	}
+	bc.Bcast("lyle")
+	if got := bc.Replay(5); !reflect.DeepEqual(got, []string{"bill", "lyle"}) {
+		t.Fatalf("unexpected history %v", got)
+	}
+	if !strings.Contains(buf.String(), "bchan: Bcast seq=2") {
+		t.Fatalf("expected Bcast to be logged, got %q", buf.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("mistyped WithInitial should panic")
+		}
+	}()
+	bchan.NewOfWithOptions[int](bchan.WithInitial("not an int"))
+}
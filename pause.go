@@ -0,0 +1,44 @@
+package bchan
+
+// Pause quiesces receivers without turning
+// broadcasting off: Ch is drained and is not
+// refilled, subscriptions stop delivering, and
+// Stopped() does not fire. The value and the
+// on/off state are kept, and may even be changed
+// while paused; Resume picks up where things stand.
+func (b *Of[T]) Pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed || b.paused {
+		return
+	}
+	b.logf("bchan: Pause seq=%d", b.seq)
+	b.paused = true
+	b.drain()
+	b.publish(b.live)
+	b.emit("Pause")
+}
+
+// Resume undoes Pause, refilling Ch with the
+// current value if broadcasting is on.
+func (b *Of[T]) Resume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed || !b.paused {
+		return
+	}
+	b.logf("bchan: Resume seq=%d", b.seq)
+	b.paused = false
+	if b.on && b.live {
+		b.fill()
+	}
+	b.publish(b.live)
+	b.emit("Resume")
+}
+
+// IsPaused reports whether b is paused.
+func (b *Of[T]) IsPaused() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.paused
+}
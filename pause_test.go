@@ -0,0 +1,45 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestPauseResume(t *testing.T) {
+
+	bc := bchan.New(2)
+	sub := bc.Subscribe()
+	defer bc.Unsubscribe(sub)
+	bc.Bcast("bill")
+	stopped := bc.Stopped()
+
+	bc.Pause()
+	if len(bc.Ch) != 0 {
+		t.Fatal("Pause should drain Ch")
+	}
+	bc.BcastAck()
+	if len(bc.Ch) != 0 {
+		t.Fatal("acks must not refill while paused")
+	}
+	select {
+	case <-sub.Ch:
+		t.Fatal("subscriptions should be quiet while paused")
+	case <-stopped:
+		t.Fatal("Pause is not Off")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if v, ok := bc.Cur(); !ok || v != "bill" {
+		t.Fatal("Pause must keep the value and on-intent")
+	}
+
+	bc.Bcast("lyle") // allowed while paused, takes effect on Resume
+	bc.Resume()
+	if v := <-bc.Ch; v != "lyle" {
+		t.Fatalf("expected lyle after Resume, got %v", v)
+	}
+	if v := <-sub.Ch; v != "lyle" {
+		t.Fatalf("expected subscription to resume with lyle, got %v", v)
+	}
+}
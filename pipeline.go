@@ -0,0 +1,105 @@
+package bchan
+
+import (
+	"context"
+)
+
+// Pipeline describes a Bchan derived from another
+// by a series of filter and map stages; see Derive.
+type Pipeline[T any] struct {
+	src    *Of[T]
+	stages []func(v T) (T, bool)
+}
+
+// Derive starts a Pipeline from src. Add stages with
+// Filter and Map, then call Build for the derived
+// Bchan:
+//
+//	hot := bchan.Derive(temps).Filter(over30).Map(round).Build(4)
+//
+// To derive a Bchan of another type, use Link.
+func Derive[T any](src *Of[T]) *Pipeline[T] {
+	return &Pipeline[T]{src: src}
+}
+
+// Filter adds a stage that passes on only the
+// values for which keep returns true. A value that
+// is filtered out leaves the derived Bchan's value
+// as it was.
+func (p *Pipeline[T]) Filter(keep func(v T) bool) *Pipeline[T] {
+	p.stages = append(p.stages, func(v T) (T, bool) {
+		return v, keep(v)
+	})
+	return p
+}
+
+// Map adds a stage that replaces each value
+// with fn(value).
+func (p *Pipeline[T]) Map(fn func(v T) T) *Pipeline[T] {
+	p.stages = append(p.stages, func(v T) (T, bool) {
+		return fn(v), true
+	})
+	return p
+}
+
+// Build makes the derived Bchan, of the given
+// diameter (see New), and starts the package-owned
+// goroutine that keeps it in step with the source:
+// each new source value that makes it through every
+// stage is broadcast on it (or Set, if the source is
+// off), and once it has a value it turns on and off
+// with the source. The stages run on that goroutine,
+// once per new value, skipping to the latest under a
+// burst of updates. Closing the derived Bchan stops
+// the goroutine; closing the source closes the
+// derived Bchan too.
+func (p *Pipeline[T]) Build(diameter int) *Of[T] {
+	out := NewOf[T](diameter)
+	stages := append([]func(T) (T, bool){}, p.stages...)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// stop following once out is closed.
+		wake, _ := out.watch()
+		for range wake {
+		}
+		cancel()
+	}()
+	go func() {
+		defer out.Close()
+		var seen uint64
+		have := false
+		p.src.Follow(ctx, func(v T, seq uint64, on bool) error {
+			pass := false
+			if seq != seen {
+				seen = seq
+				pass = true
+				for _, s := range stages {
+					if v, pass = s(v); !pass {
+						break
+					}
+				}
+			}
+			out.lockForBcast()
+			defer out.mu.Unlock()
+			if out.closed {
+				return ErrClosed
+			}
+			switch {
+			case pass && on:
+				out.bcast("Derive", v)
+			case pass:
+				out.cancelPending()
+				out.store("Derive", v)
+				out.drain()
+				out.publish(false)
+			case have && on && !out.on:
+				out.turnOn()
+			case have && !on && out.on:
+				out.off()
+			}
+			have = have || pass
+			return nil
+		})
+	}()
+	return out
+}
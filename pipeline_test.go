@@ -0,0 +1,31 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestDerive(t *testing.T) {
+
+	src := bchan.NewOf[int](1)
+	evensDoubled := bchan.Derive(src).
+		Filter(func(v int) bool { return v%2 == 0 }).
+		Map(func(v int) int { return v * 2 }).
+		Build(2)
+
+	src.Bcast(2)
+	eventually(t, "the first derived value", func() bool {
+		v, on := evensDoubled.Cur()
+		return on && v == 4
+	})
+	src.Bcast(3)
+	src.Bcast(6)
+	eventually(t, "odd values to be skipped", func() bool { return evensDoubled.Get() == 12 })
+
+	src.Off()
+	eventually(t, "the derived Bchan to follow Off", func() bool { return !evensDoubled.IsOn() })
+
+	src.Close()
+	eventually(t, "the derived Bchan to close", evensDoubled.IsClosed)
+}
@@ -0,0 +1,66 @@
+package bchan
+
+import (
+	"sync"
+)
+
+// Pool recycles Bchans. See PoolOf.
+type Pool = PoolOf[interface{}]
+
+// PoolOf hands out Bchans of one diameter for
+// workloads that make short-lived broadcasters,
+// say one per request, at a high rate. The costly
+// part of a Bchan is its diameter-sized Ch, and a
+// pool keeps those for reuse; every Bchan from Get
+// otherwise starts fresh, off and with a zero value
+// and no settings, just as from NewOf.
+type PoolOf[T any] struct {
+	diameter int
+	chans    sync.Pool
+}
+
+// NewPool makes a pool of Bchans of the given
+// expectedDiameter; see New.
+func NewPool[T any](expectedDiameter int) *PoolOf[T] {
+	if expectedDiameter <= 0 {
+		expectedDiameter = 1
+	}
+	return &PoolOf[T]{diameter: expectedDiameter}
+}
+
+// Get returns a fresh Bchan, reusing a pooled Ch
+// if there is one.
+func (p *PoolOf[T]) Get() *Of[T] {
+	ch, ok := p.chans.Get().(chan T)
+	if !ok {
+		return NewOf[T](p.diameter)
+	}
+	b := &Of[T]{id: nextID.Add(1)}
+	b.setCh(ch)
+	return b
+}
+
+// Put shuts b down, as Close would, and keeps its
+// Ch for a later Get. Unlike after Close, b's Ch is
+// not closed to wake receivers, since it lives on:
+// everyone must be done with b, and with its Ch,
+// before Put. A Bchan that has been resized or
+// already closed is not pooled; Put just closes it.
+func (p *PoolOf[T]) Put(b *Of[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.logf("bchan: Put seq=%d", b.seq)
+	if cap(b.Ch) != p.diameter+1 {
+		b.shutdown()
+		close(b.Ch)
+		return
+	}
+	b.shutdown()
+	ch := b.Ch
+	b.setCh(nil)
+	drainCh(ch)
+	p.chans.Put(ch)
+}
@@ -0,0 +1,47 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestPool(t *testing.T) {
+
+	p := bchan.NewPool[int](4)
+	b := p.Get()
+	b.Bcast(7)
+	if v := <-b.Ch; v != 7 {
+		t.Fatalf("got %d, want 7", v)
+	}
+	b.BcastAck()
+	p.Put(b)
+	if !b.IsClosed() {
+		t.Fatal("Put should shut the Bchan down")
+	}
+
+	for i := 0; i < 3; i++ {
+		b = p.Get()
+		if b.IsOn() || b.Get() != 0 || b.Seq() != 0 || b.IsClosed() {
+			t.Fatalf("Get returned a Bchan with old state: on=%v val=%d seq=%d", b.IsOn(), b.Get(), b.Seq())
+		}
+		if cap(b.Ch) != 5 || len(b.Ch) != 0 {
+			t.Fatalf("pooled Ch has len %d cap %d", len(b.Ch), cap(b.Ch))
+		}
+		b.Bcast(i)
+		if v := <-b.Ch; v != i {
+			t.Fatalf("got %d, want %d", v, i)
+		}
+		b.BcastAck()
+		p.Put(b)
+	}
+
+	// a resized Bchan is closed, not pooled.
+	b = p.Get()
+	b.Resize(10)
+	ch := b.Ch
+	p.Put(b)
+	if _, ok := <-ch; ok {
+		t.Fatal("a resized Bchan should be closed by Put")
+	}
+}
@@ -0,0 +1,26 @@
+package bchan
+
+import "runtime/pprof"
+
+// WithProfileLabels runs b's package-owned goroutines
+// (observer and OnChange dispatch, subscription
+// delivery) under the pprof label bchan=name, so CPU
+// profiles of a service with many Bchans attribute the
+// time to the right one.
+//
+// Fill and drain work done on a caller's goroutine
+// (On, Bcast, BcastAck, ...) is not labelled: b never
+// touches the labels of a goroutine it did not start.
+func WithProfileLabels(name string) Option {
+	return func(c *config) {
+		c.profName = name
+	}
+}
+
+// labelGoroutine gives the calling goroutine, which
+// must be one the package started for b, b's labels.
+func (b *Of[T]) labelGoroutine() {
+	if b.profCtx != nil {
+		pprof.SetGoroutineLabels(b.profCtx)
+	}
+}
@@ -0,0 +1,65 @@
+package bchan_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+const cfgLabel = `"bchan":"cfg"`
+
+func goroutineProfile(t *testing.T) string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestProfileLabelsOnDispatch(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[int](bchan.WithProfileLabels("cfg"))
+	defer bc.Close()
+	entered := make(chan string)
+	release := make(chan struct{})
+	cancel := bc.OnChange(func(v int) {
+		entered <- goroutineProfile(t)
+		<-release
+	})
+	defer cancel()
+
+	bc.Bcast(1)
+	prof := <-entered
+	close(release)
+	if !strings.Contains(prof, cfgLabel) {
+		t.Fatalf("OnChange dispatcher not labeled:\n%s", prof)
+	}
+}
+
+func TestProfileLabelsKeepCallers(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[int](bchan.WithProfileLabels("cfg"))
+	var during string
+	bc.InterceptDeliver(func(v int, next func(v int) int) int {
+		if during == "" {
+			during = goroutineProfile(t)
+		}
+		return next(v)
+	})
+	var after string
+	pprof.Do(context.Background(), pprof.Labels("caller", "mine"), func(context.Context) {
+		bc.Bcast(1)
+		after = goroutineProfile(t)
+	})
+	const mine = `"caller":"mine"`
+	if !strings.Contains(during, mine) {
+		t.Fatalf("caller's goroutine relabelled during Bcast:\n%s", during)
+	}
+	if !strings.Contains(after, mine) {
+		t.Fatalf("caller's labels lost after Bcast:\n%s", after)
+	}
+}
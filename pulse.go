@@ -0,0 +1,69 @@
+package bchan
+
+// Pulse is edge-triggered delivery: val goes once
+// to each goroutine that is already blocked
+// receiving on Ch (or on a consumer group's Ch,
+// or on a subscription), and nowhere else. It is
+// never queued, so nothing sticky is left behind,
+// and the current value and on/off state are
+// untouched. Pulse returns how many receivers got val.
+//
+// Note that while broadcasting is on and Ch is
+// stocked, no receiver is blocked on it, so Pulse
+// is best used on a Bchan that is otherwise off.
+func (b *Of[T]) Pulse(val T) (n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("Pulse") {
+		return 0
+	}
+	n = pulseCh(b.Ch, val, b.handout)
+	for _, g := range b.groups {
+		n += pulseCh(g.Ch, val, b.handout)
+	}
+	if len(b.subs) > 0 {
+		res := make(chan bool, len(b.subs))
+		st := subState[T]{val: val, pulse: res, out: b.out}
+		for s := range b.subs {
+			s.update <- st
+		}
+		for range b.subs {
+			if <-res {
+				n++
+			}
+		}
+	}
+	return
+}
+
+// pulseCh hands val to receivers waiting on ch.
+// When receivers are parked on a channel its buffer
+// is empty, and the runtime gives a send straight to
+// one of them; so a send that leaves len(ch) at 0 went
+// to a waiter. The first send that lands in the buffer
+// instead means nobody is left waiting, and is taken
+// back out. Each send is of cp(val).
+// Caller must hold b.mu.
+func pulseCh[T any](ch chan T, val T, cp func(T) T) (n int) {
+	if len(ch) != 0 {
+		return 0
+	}
+	for {
+		select {
+		case ch <- cp(val):
+		default:
+			return
+		}
+		if len(ch) == 0 {
+			n++
+			continue
+		}
+		select {
+		case <-ch:
+		default:
+			// a late receiver took it anyway.
+			n++
+		}
+		return
+	}
+}
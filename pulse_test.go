@@ -0,0 +1,56 @@
+package bchan_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestPulse(t *testing.T) {
+
+	bc := bchan.New(4)
+	sub := bc.Subscribe()
+	defer bc.Unsubscribe(sub)
+
+	if n := bc.Pulse("flush"); n != 0 {
+		t.Fatalf("nobody waiting, yet Pulse delivered to %v", n)
+	}
+	if len(bc.Ch) != 0 {
+		t.Fatal("Pulse must not leave a value queued")
+	}
+
+	var wg sync.WaitGroup
+	got := make(chan interface{}, 10)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got <- <-bc.Ch
+			bc.BcastAck()
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		got <- <-sub.Ch
+	}()
+
+	// give the receivers time to block.
+	time.Sleep(50 * time.Millisecond)
+	if n := bc.Pulse("flush"); n != 4 {
+		t.Fatalf("expected 4 deliveries, got %v", n)
+	}
+	wg.Wait()
+	close(got)
+	for v := range got {
+		if v != "flush" {
+			t.Fatalf("expected flush, got %v", v)
+		}
+	}
+
+	if len(bc.Ch) != 0 {
+		t.Fatal("acks after a Pulse must not refill")
+	}
+}
@@ -0,0 +1,136 @@
+package bchan
+
+// WithQueue switches a subscription from latest-value
+// to event-stream delivery: every new value broadcast
+// while it is subscribed is delivered once, in order,
+// rather than the current value over and over. Values
+// wait in a queue of up to n (at least 1) for the
+// subscriber to take them; if it falls that far
+// behind, the oldest are discarded and counted by
+// Dropped. Once the queue is empty, Ch just blocks
+// until the next broadcast.
+func WithQueue(n int) SubOption {
+	return func(c *subConfig) {
+		if n < 1 {
+			n = 1
+		}
+		c.queue = n
+	}
+}
+
+// WithConflate makes a WithQueue subscription
+// conflate by key, as market-data feeds do: a new
+// value whose key(v) matches that of one still
+// waiting in the queue replaces it there, keeping
+// its place in line, instead of queueing behind it.
+// Each key then holds at most one place in the
+// queue, always with its freshest value, which
+// bounds the queue by the number of distinct keys.
+// Replaced values count as Dropped. key must be a
+// func(T) K for the T of the Bchan subscribed to,
+// and K must be comparable. Without WithQueue,
+// WithConflate has no effect.
+func WithConflate[T any, K comparable](key func(v T) K) SubOption {
+	return func(c *subConfig) {
+		c.conflate = func(v T) interface{} { return key(v) }
+	}
+}
+
+// WithoutCurrent makes a WithQueue subscription
+// leave out the value current when it is made, and
+// deliver only those broadcast after. It is for
+// callers that have read the current state some
+// other way, at the same instant, such as with
+// Window. Without WithQueue, WithoutCurrent has no
+// effect.
+func WithoutCurrent() SubOption {
+	return func(c *subConfig) {
+		c.skipCur = true
+	}
+}
+
+// queued is a value waiting in a
+// WithQueue subscription's queue.
+type queued[T any] struct {
+	val T
+	seq uint64 // 0 for backlog
+	q   *Quorum
+	key interface{}
+}
+
+// deliverQueued is deliver for a WithQueue
+// subscription.
+func (s *SubscriptionOf[T]) deliverQueued(st subState[T], backlog []T) {
+	defer close(s.ch)
+	s.b.labelGoroutine()
+	var queue []*queued[T]
+	for _, v := range s.prepBacklog(backlog) {
+		queue = append(queue, &queued[T]{val: v})
+	}
+	// byKey finds the waiting value for each key;
+	// only used WithConflate.
+	byKey := make(map[interface{}]*queued[T])
+	var last uint64
+	if s.skipCur {
+		last = st.seq
+	}
+	add := func(st subState[T]) {
+		if !st.live || st.seq == 0 || st.seq == last {
+			return
+		}
+		last = st.seq
+		if !s.wants(st) {
+			return
+		}
+		it := &queued[T]{val: s.xform(st.val, true), seq: st.seq, q: st.q}
+		if s.conflate != nil {
+			it.key = s.conflate(st.val)
+			if old, ok := byKey[it.key]; ok {
+				*old = *it
+				s.ndrop.Add(1)
+				return
+			}
+			byKey[it.key] = it
+		}
+		queue = append(queue, it)
+		if len(queue) > s.queue {
+			if s.conflate != nil {
+				delete(byKey, queue[0].key)
+			}
+			queue = queue[1:]
+			s.ndrop.Add(1)
+		}
+	}
+	add(st)
+	for {
+		var out chan T
+		var next T
+		if len(queue) > 0 {
+			out, next = s.ch, st.handout(queue[0].val)
+		}
+		select {
+		case out <- next:
+			it := queue[0]
+			queue = queue[1:]
+			if s.conflate != nil && byKey[it.key] == it {
+				delete(byKey, it.key)
+			}
+			if it.seq != 0 {
+				s.lastSeq.Store(it.seq)
+				s.nrecv.Add(1)
+			}
+			if it.q != nil {
+				it.q.confirm(s)
+			}
+		case nst := <-s.update:
+			if nst.pulse != nil {
+				s.pulse(nst)
+				continue
+			}
+			st = nst
+			add(st)
+		case <-s.done:
+			return
+		}
+	}
+}
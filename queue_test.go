@@ -0,0 +1,90 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestWithQueue(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	sub := bc.Subscribe(bchan.WithQueue(3))
+	defer bc.Unsubscribe(sub)
+
+	for i := 1; i <= 5; i++ {
+		bc.Bcast(i)
+	}
+	// 1 and 2 fell off the queue.
+	for want := 3; want <= 5; want++ {
+		if v := <-sub.Ch; v != want {
+			t.Fatalf("got %d, want %d", v, want)
+		}
+	}
+	if d := sub.Dropped(); d != 2 {
+		t.Fatalf("Dropped is %d, want 2", d)
+	}
+	select {
+	case v := <-sub.Ch:
+		t.Fatalf("got %d again; a queue delivers each value once", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+	bc.Bcast(6)
+	if v := <-sub.Ch; v != 6 {
+		t.Fatalf("got %d, want 6", v)
+	}
+}
+
+func TestWithConflate(t *testing.T) {
+
+	type quote struct {
+		Sym   string
+		Price int
+	}
+	bc := bchan.NewOf[quote](1)
+	sub := bc.Subscribe(bchan.WithQueue(10), bchan.WithConflate(func(q quote) string { return q.Sym }))
+	defer bc.Unsubscribe(sub)
+
+	bc.Bcast(quote{"IBM", 1})
+	bc.Bcast(quote{"AAPL", 1})
+	bc.Bcast(quote{"IBM", 2})
+	bc.Bcast(quote{"IBM", 3})
+
+	want := []quote{{"IBM", 3}, {"AAPL", 1}}
+	for _, w := range want {
+		if q := <-sub.Ch; q != w {
+			t.Fatalf("got %v, want %v", q, w)
+		}
+	}
+	if d := sub.Dropped(); d != 2 {
+		t.Fatalf("Dropped is %d, want the 2 conflated quotes", d)
+	}
+
+	// once sent, a key queues afresh.
+	bc.Bcast(quote{"IBM", 4})
+	if q := <-sub.Ch; q != (quote{"IBM", 4}) {
+		t.Fatalf("got %v, want IBM 4", q)
+	}
+}
+
+func TestWithoutCurrent(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(1)
+
+	all := bc.Subscribe(bchan.WithQueue(3))
+	defer bc.Unsubscribe(all)
+	after := bc.Subscribe(bchan.WithQueue(3), bchan.WithoutCurrent())
+	defer bc.Unsubscribe(after)
+
+	bc.Bcast(2)
+	for _, want := range []int{1, 2} {
+		if v := <-all.Ch; v != want {
+			t.Fatalf("got %d, want %d", v, want)
+		}
+	}
+	if v := <-after.Ch; v != 2 {
+		t.Fatalf("got %d; WithoutCurrent should skip 1 and start at 2", v)
+	}
+}
@@ -0,0 +1,97 @@
+package bchan
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// Quorum resolves once enough subscriptions have
+// received a value sent by BcastQuorum.
+type Quorum struct {
+	mu   sync.Mutex
+	need int
+	seen map[interface{}]bool
+	done chan struct{}
+}
+
+func newQuorum(need int) *Quorum {
+	q := &Quorum{
+		need: need,
+		seen: make(map[interface{}]bool),
+		done: make(chan struct{}),
+	}
+	if need <= 0 {
+		close(q.done)
+	}
+	return q
+}
+
+// confirm records that who has received the value.
+// Repeat confirmations from the same who count once.
+func (q *Quorum) confirm(who interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.seen[who] {
+		return
+	}
+	q.seen[who] = true
+	if len(q.seen) == q.need {
+		close(q.done)
+	}
+}
+
+// Done is closed once the quorum is reached.
+func (q *Quorum) Done() <-chan struct{} {
+	return q.done
+}
+
+// Wait blocks until the quorum is reached or ctx is done.
+func (q *Quorum) Wait(ctx context.Context) error {
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Confirmed reports how many distinct subscriptions
+// have received the value so far, and how many
+// are needed.
+func (q *Quorum) Confirmed() (got, need int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.seen), q.need
+}
+
+// BcastQuorum is Bcast(val) for consensus-style
+// rollouts: the returned Quorum resolves once at
+// least fraction (0 to 1) of the subscriptions
+// registered at the time of the call have actually
+// received val on their Ch, so that most of the
+// fleet is known to have seen the new epoch.
+// Receivers on the shared Ch are not counted,
+// since they cannot be told apart; subscriptions
+// can. With no subscriptions the Quorum is
+// already resolved, and so it is if val is not
+// broadcast: invalid, over the rate limit, or held
+// back by SetBackpressure. BcastQuorum is never
+// debounced.
+func (b *Of[T]) BcastQuorum(val T, fraction float64) *Quorum {
+	b.lockForBcast()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("BcastQuorum") || b.admitNow("BcastQuorum", &val, true) != nil {
+		return newQuorum(0)
+	}
+	need := int(math.Ceil(fraction * float64(len(b.subs))))
+	if need > len(b.subs) {
+		need = len(b.subs)
+	}
+	b.cancelPending()
+	b.store("BcastQuorum", val)
+	q := newQuorum(need)
+	b.quorum = q
+	b.activate()
+	return q
+}
@@ -0,0 +1,44 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestBcastQuorum(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	if q := bc.BcastQuorum(1, 1); q.Wait(context.Background()) != nil {
+		t.Fatal("no subscriptions means the quorum is trivially met")
+	}
+
+	subs := make([]*bchan.SubscriptionOf[int], 4)
+	for i := range subs {
+		subs[i] = bc.Subscribe()
+		defer bc.Unsubscribe(subs[i])
+	}
+
+	q := bc.BcastQuorum(2, 0.5)
+	if got, need := q.Confirmed(); got != 0 || need != 2 {
+		t.Fatalf("expected 0 of 2, got %v of %v", got, need)
+	}
+
+	// the same subscriber receiving twice counts once.
+	<-subs[0].Ch
+	<-subs[0].Ch
+	select {
+	case <-q.Done():
+		t.Fatal("one distinct receipt is not a quorum of 2")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	<-subs[1].Ch
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := q.Wait(ctx); err != nil {
+		t.Fatalf("expected quorum after two subscribers received, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package bchan
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrRateLimited is returned by TryBcast and the
+// other immediate broadcasts (BcastAndWait,
+// BcastSync, BcastRequest, Ask, AtomicGroup.Bcast)
+// when the rate limit set by SetRateLimit is
+// exceeded.
+var ErrRateLimited = errors.New("bchan: rate limited")
+
+// SetRateLimit caps Bcast at perSecond values per
+// second on average, with bursts of up to burst, so
+// that consumers doing expensive work per update are
+// protected from a runaway producer. A Bcast over the
+// limit is either coalesced, becoming a pending value
+// broadcast (if still the latest) once the rate allows,
+// or, if reject is true, dropped. TryBcast reports
+// the latter case as ErrRateLimited.
+// perSecond <= 0 removes the limit.
+func (b *Of[T]) SetRateLimit(perSecond float64, burst int, reject bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if perSecond <= 0 {
+		b.limit = nil
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	b.limit = &bucket{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   b.now(),
+		reject: reject,
+	}
+}
+
+// TryBcast is Bcast that never waits and never
+// coalesces: if the rate limit leaves no room right
+// now, or a coalesced value is already pending, it
+// returns ErrRateLimited and broadcasts nothing,
+// and likewise ErrBackpressure if the current value
+// still lacks the acks SetBackpressure asks for.
+func (b *Of[T]) TryBcast(val T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("TryBcast") {
+		return ErrClosed
+	}
+	if b.backpressured() {
+		b.ndropped.Add(1)
+		return ErrBackpressure
+	}
+	if err := b.admit("TryBcast", &val); err != nil {
+		return err
+	}
+	if b.hasPending || b.limit != nil && !b.limit.take(b.now()) {
+		b.ndropped.Add(1)
+		return ErrRateLimited
+	}
+	b.store("TryBcast", val)
+	b.activate()
+	return nil
+}
+
+// bucket is a token bucket.
+type bucket struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	reject bool
+}
+
+func (k *bucket) refill(now time.Time) {
+	k.tokens = math.Min(k.burst, k.tokens+now.Sub(k.last).Seconds()*k.rate)
+	k.last = now
+}
+
+// take spends a token if one is available.
+func (k *bucket) take(now time.Time) bool {
+	k.refill(now)
+	if k.tokens >= 1 {
+		k.tokens--
+		return true
+	}
+	return false
+}
+
+// wait is how long until a token is available.
+func (k *bucket) wait(now time.Time) time.Duration {
+	k.refill(now)
+	if k.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - k.tokens) / k.rate * float64(time.Second))
+}
@@ -0,0 +1,106 @@
+package bchan_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestRateLimitReject(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.SetRateLimit(1, 2, true)
+	if bc.TryBcast(1) != nil || bc.TryBcast(2) != nil {
+		t.Fatal("burst of 2 should be allowed")
+	}
+	if err := bc.TryBcast(3); err != bchan.ErrRateLimited {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	bc.Bcast(4) // dropped
+	if v := bc.Get(); v != 2 {
+		t.Fatalf("expected 2 to remain current, got %v", v)
+	}
+}
+
+func TestRateLimitCoalesce(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.SetRateLimit(20, 1, false) // one per 50ms
+	for i := 1; i <= 10; i++ {
+		bc.Bcast(i)
+	}
+	if v := bc.Get(); v != 1 {
+		t.Fatalf("only the first should go out at once, got %v", v)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if v := bc.Get(); v != 10 {
+		t.Fatalf("the rest should coalesce into the latest, got %v", v)
+	}
+	if s := bc.Seq(); s != 2 {
+		t.Fatalf("expected two broadcasts in all, got %v", s)
+	}
+}
+
+func TestTryBcastBackpressureKeepsBudget(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.SetRateLimit(0.001, 2, true)
+	bc.SetBackpressure(1, 0)
+	if err := bc.TryBcast(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := bc.TryBcast(2); err != bchan.ErrBackpressure {
+		t.Fatalf("expected ErrBackpressure, got %v", err)
+	}
+	<-bc.Ch
+	bc.BcastAck()
+	if err := bc.TryBcast(3); err != nil {
+		t.Fatalf("backpressured TryBcast spent the rate budget: %v", err)
+	}
+}
+
+// held makes b refuse what comes after v, for
+// the rate limit or, if !rate, for backpressure.
+func held[T any](b *bchan.Of[T], v T, rate bool) {
+	if rate {
+		b.SetRateLimit(0.001, 1, true)
+	} else {
+		b.SetBackpressure(1, 0)
+	}
+	b.Bcast(v)
+}
+
+func TestImmediateBcastsHeld(t *testing.T) {
+
+	for _, rate := range []bool{true, false} {
+		want := bchan.ErrBackpressure
+		if rate {
+			want = bchan.ErrRateLimited
+		}
+		bc := bchan.NewOf[int](1)
+		held(bc, 1, rate)
+		bc.BcastTTL(2, time.Hour)
+		if bc.CompareAndBcast(1, 3) {
+			t.Errorf("rate=%v: CompareAndBcast went out", rate)
+		}
+		bc.BcastQuorum(4, 1)
+		if _, err := bc.BcastRequest(5, time.Millisecond); err != want {
+			t.Errorf("rate=%v: BcastRequest gave %v", rate, err)
+		}
+		other := bchan.NewOf[int](1)
+		if err := bchan.NewAtomicGroup(bc, other).Bcast(6, 6); !errors.Is(err, want) {
+			t.Errorf("rate=%v: AtomicGroup.Bcast gave %v", rate, err)
+		}
+		if bc.Seq() != 1 || bc.Get() != 1 || other.IsOn() {
+			t.Errorf("rate=%v: held broadcasts went out: seq %v, val %v", rate, bc.Seq(), bc.Get())
+		}
+
+		ab := bchan.NewOf[bchan.AskOf[int, int]](1)
+		held(ab, bchan.AskOf[int, int]{}, rate)
+		if _, err := bchan.Ask(ab, 7, time.Millisecond); err != want || ab.Seq() != 1 {
+			t.Errorf("rate=%v: Ask gave %v at seq %v", rate, err, ab.Seq())
+		}
+	}
+}
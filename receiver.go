@@ -0,0 +1,198 @@
+package bchan
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Receiver is a named consumer of a Bchan's Ch.
+// See ReceiverOf.
+type Receiver = ReceiverOf[interface{}]
+
+// ReceiverOf is a consumer registered by name with
+// RegisterReceiver, so that its receives, acks and
+// misses can be told apart from everyone else's: in
+// Receivers, in what CheckAcks and SetAckDeadline
+// report, and in b's log. It receives from the same
+// Ch as anonymous receivers, under the same rule:
+// every Recv must be followed by an Ack.
+type ReceiverOf[T any] struct {
+	Name string
+	b    *Of[T]
+
+	// guarded by b.mu
+	nrecv   uint64
+	nack    uint64
+	nmiss   uint64
+	lastSeq uint64
+	holding bool
+	since   time.Time
+
+	// exact is set for ExactlyOnce receivers;
+	// see exactly.go.
+	exact *exactState[T]
+}
+
+// ReceiverStats is a snapshot of one
+// registered receiver's accounts.
+type ReceiverStats struct {
+	Name string
+
+	// Received and Acked count its
+	// receives and acks.
+	Received uint64
+	Acked    uint64
+
+	// Missed counts broadcasts it never saw
+	// because it came back for the next value
+	// only after they were replaced; see Lag.
+	Missed uint64
+
+	// LastSeq is the sequence number (see Seq)
+	// current at its last receive.
+	LastSeq uint64
+
+	// Holding is true if it has received and
+	// not yet acked, since HeldSince.
+	Holding   bool
+	HeldSince time.Time
+}
+
+// ReceiverOption configures a receiver;
+// pass them to RegisterReceiver.
+type ReceiverOption func(c *receiverConfig)
+
+type receiverConfig struct {
+	exact bool
+}
+
+// RegisterReceiver returns the receiver called
+// name, registering it if it is new; opts only
+// apply to a new one.
+func (b *Of[T]) RegisterReceiver(name string, opts ...ReceiverOption) *ReceiverOf[T] {
+	var cfg receiverConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r, ok := b.receivers[name]; ok {
+		return r
+	}
+	if b.receivers == nil {
+		b.receivers = make(map[string]*ReceiverOf[T])
+	}
+	r := &ReceiverOf[T]{Name: name, b: b}
+	if cfg.exact {
+		r.exact = newExactState[T](b.seq)
+	}
+	b.receivers[name] = r
+	return r
+}
+
+// Unregister removes r from b's Receivers. It
+// may still be used, but is no longer listed.
+func (r *ReceiverOf[T]) Unregister() {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if r.b.receivers[r.Name] == r {
+		delete(r.b.receivers, r.Name)
+	}
+}
+
+// Receivers reports on every registered
+// receiver, in order of name.
+func (b *Of[T]) Receivers() []ReceiverStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	out := make([]ReceiverStats, 0, len(b.receivers))
+	for _, r := range b.receivers {
+		out = append(out, r.stats())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Stats reports on r alone.
+func (r *ReceiverOf[T]) Stats() ReceiverStats {
+	r.b.mu.RLock()
+	defer r.b.mu.RUnlock()
+	return r.stats()
+}
+
+// caller must hold r.b.mu.
+func (r *ReceiverOf[T]) stats() ReceiverStats {
+	return ReceiverStats{
+		Name:      r.Name,
+		Received:  r.nrecv,
+		Acked:     r.nack,
+		Missed:    r.nmiss,
+		LastSeq:   r.lastSeq,
+		Holding:   r.holding,
+		HeldSince: r.since,
+	}
+}
+
+// Recv receives from Ch for r, without the ack;
+// call Ack once done with the value. It returns
+// ctx.Err() if ctx is done first, and ErrClosed
+// once b is closed. The sequence number a value is
+// counted under is read just after the receive, so
+// a broadcast racing with it can be counted
+// against the wrong value.
+func (r *ReceiverOf[T]) Recv(ctx context.Context) (val T, err error) {
+	if r.exact != nil {
+		return r.recvExact(ctx)
+	}
+	b := r.b
+	select {
+	case v, ok := <-b.ch():
+		if !ok {
+			return val, ErrClosed
+		}
+		b.received(recvDepth, r.Name)
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		r.nrecv++
+		if r.lastSeq != 0 && b.seq > r.lastSeq+1 {
+			missed := b.seq - r.lastSeq - 1
+			r.nmiss += missed
+			b.logf("bchan: receiver %q missed %d broadcasts before seq=%d", r.Name, missed, b.seq)
+		}
+		if b.seq > r.lastSeq {
+			r.lastSeq = b.seq
+		}
+		if r.holding {
+			b.logf("bchan: receiver %q received again without acking", r.Name)
+		}
+		r.holding = true
+		r.since = b.now()
+		return v, nil
+	case <-ctx.Done():
+		return val, ctx.Err()
+	}
+}
+
+// Ack is BcastAck, made by r. For an ExactlyOnce
+// receiver, which does not use Ch, it only
+// updates r's accounts.
+func (r *ReceiverOf[T]) Ack() {
+	b := r.b
+	b.mu.Lock()
+	if !r.holding {
+		b.logf("bchan: receiver %q acked with nothing received", r.Name)
+	}
+	r.holding = false
+	r.nack++
+	b.mu.Unlock()
+	if r.exact == nil {
+		b.ackAs(r.Name)
+	}
+}
+
+// ackAs is BcastAck by the receiver named who,
+// at the same call depth, for callSite.
+func (b *Of[T]) ackAs(who string) {
+	b.ackBy(nil, who)
+}
@@ -0,0 +1,54 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestRegisterReceiver(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	flusher := bc.RegisterReceiver("metrics-flusher")
+	if bc.RegisterReceiver("metrics-flusher") != flusher {
+		t.Fatal("registering a name twice should return the same receiver")
+	}
+	ctx := context.Background()
+	var got []bchan.AckViolation
+	bc.CheckAcks(time.Minute, func(v bchan.AckViolation) {
+		got = append(got, v)
+	})
+
+	bc.Bcast(1)
+	if _, err := flusher.Recv(ctx); err != nil {
+		t.Fatal(err)
+	}
+	flusher.Ack()
+	bc.Bcast(2)
+	bc.Bcast(3)
+	if v, err := flusher.Recv(ctx); err != nil || v != 3 {
+		t.Fatalf("got %d, %v; want 3, nil", v, err)
+	}
+
+	st := bc.Receivers()
+	if len(st) != 1 {
+		t.Fatalf("got %d receivers, want 1", len(st))
+	}
+	s := st[0]
+	if s.Name != "metrics-flusher" || s.Received != 2 || s.Acked != 1 || s.Missed != 1 || s.LastSeq != 3 || !s.Holding {
+		t.Fatalf("bad stats %+v", s)
+	}
+
+	flusher.Ack()
+	flusher.Ack()
+	if len(got) != 1 || got[0].Receiver != "metrics-flusher" || got[0].Kind != bchan.SpuriousAck {
+		t.Fatalf("want a spurious ack by metrics-flusher, got %v", got)
+	}
+
+	flusher.Unregister()
+	if len(bc.Receivers()) != 0 {
+		t.Fatal("Unregister left the receiver listed")
+	}
+}
@@ -0,0 +1,72 @@
+package bchan
+
+import (
+	"context"
+)
+
+// Recv blocks until a broadcast value is
+// available on Ch, does the BcastAck for you,
+// and returns the value. If ctx is done first,
+// Recv returns ctx.Err() instead. Once b is
+// closed, Recv returns ErrClosed.
+func (b *Of[T]) Recv(ctx context.Context) (val T, err error) {
+	select {
+	case v, ok := <-b.ch():
+		if !ok {
+			return val, ErrClosed
+		}
+		b.received(recvDepth, "")
+		b.BcastAck()
+		return v, nil
+	case <-ctx.Done():
+		return val, ctx.Err()
+	}
+}
+
+// Await blocks until a value that satisfies ok
+// is being broadcast on b and returns it; the
+// current value counts. It follows b as Follow
+// does, with no receive or BcastAck for the
+// caller to manage, so under a burst of updates
+// a value that held only briefly may be missed.
+// It returns ctx.Err() if ctx is done first, and
+// ErrClosed once b is closed.
+func (b *Of[T]) Await(ctx context.Context, ok func(v T) bool) (val T, err error) {
+	err = b.Follow(ctx, func(v T, seq uint64, on bool) error {
+		if on && ok(v) {
+			val = v
+			return errStopped
+		}
+		return nil
+	})
+	if err == errStopped {
+		return val, nil
+	}
+	return val, err
+}
+
+// RecvCh returns Ch as receive-only, read under
+// b's lock, so holders can neither send on it nor
+// close it, and a Resize is picked up on the next
+// call. The BcastAck rule still applies to every
+// receive.
+func (b *Of[T]) RecvCh() <-chan T {
+	return b.ch()
+}
+
+// TryRecv receives from Ch without blocking and
+// does the BcastAck. It reports false if nothing
+// is being broadcast, or if b is closed.
+func (b *Of[T]) TryRecv() (val T, ok bool) {
+	select {
+	case v, open := <-b.ch():
+		if !open {
+			return val, false
+		}
+		b.received(recvDepth, "")
+		b.BcastAck()
+		return v, true
+	default:
+		return val, false
+	}
+}
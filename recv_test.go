@@ -0,0 +1,103 @@
+package bchan_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestRecv(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := bc.Recv(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("nothing broadcast; expected DeadlineExceeded, got %v", err)
+	}
+
+	bc.Bcast(7)
+	for i := 0; i < 5; i++ {
+		v, err := bc.Recv(context.Background())
+		if err != nil || v != 7 {
+			t.Fatalf("expected 7, nil; got %v, %v", v, err)
+		}
+	}
+}
+
+func TestAwait(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(1)
+	go func() {
+		for i := 2; i <= 5; i++ {
+			time.Sleep(5 * time.Millisecond)
+			bc.Bcast(i)
+		}
+	}()
+	v, err := bc.Await(context.Background(), func(v int) bool { return v >= 5 })
+	if err != nil || v != 5 {
+		t.Fatalf("expected 5, nil; got %v, %v", v, err)
+	}
+
+	v, err = bc.Await(context.Background(), func(v int) bool { return v == 5 })
+	if err != nil || v != 5 {
+		t.Fatalf("the current value should satisfy Await; got %v, %v", v, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := bc.Await(ctx, func(v int) bool { return v < 0 }); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRecvCh(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	if _, ok := bc.TryRecv(); ok {
+		t.Fatal("TryRecv succeeded while off")
+	}
+	bc.Bcast(3)
+	var ch <-chan int = bc.RecvCh()
+	if v := <-ch; v != 3 {
+		t.Fatalf("RecvCh gave %d, want 3", v)
+	}
+	bc.BcastAck()
+
+	bc.Resize(4)
+	if bc.RecvCh() == ch {
+		t.Fatal("RecvCh should follow a Resize")
+	}
+	if v, ok := bc.TryRecv(); !ok || v != 3 {
+		t.Fatalf("TryRecv got %d, %v; want 3, true", v, ok)
+	}
+
+	bc.Close()
+	if _, ok := bc.TryRecv(); ok {
+		t.Fatal("TryRecv succeeded after Close")
+	}
+}
+
+func TestAwaitHandsOutOnce(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	var calls atomic.Int32
+	bc.InterceptDeliver(func(v int, next func(int) int) int {
+		calls.Add(1)
+		return next(v) + 100
+	})
+	bc.Bcast(1)
+	before := calls.Load() // the copies put in Ch
+
+	v, err := bc.Await(context.Background(), func(v int) bool { return v > 100 })
+	if err != nil || v != 101 {
+		t.Fatalf("got %d, %v; want 101, nil", v, err)
+	}
+	if n := calls.Load() - before; n != 1 {
+		t.Fatalf("the interceptor ran %d times for one Await, want 1", n)
+	}
+}
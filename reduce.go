@@ -0,0 +1,44 @@
+package bchan
+
+// SetReducer makes new values merge into b's
+// current value instead of replacing it: Set, Bcast
+// and the other broadcast methods store
+// fn(current, incoming). This suits state that
+// several producers each contribute part of, such
+// as counters to accumulate or sets to union. If a
+// debounced or rate-limited value is pending, it is
+// what incoming merges into. The reducer runs after
+// any BcastInterceptors and before the validator,
+// with b locked; it must not call b's methods, and
+// must return a new value rather than modify cur in
+// place if receivers might still be reading it.
+// CompareAndBcast and UnmarshalBinary still replace
+// the value outright. SetReducer(nil) restores
+// plain replacement.
+func (b *Of[T]) SetReducer(fn func(cur, incoming T) T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reducer = fn
+}
+
+// WithReducer is SetReducer(fn) from the start. fn
+// must be a func(cur, incoming T) T for the T of the
+// Bchan being made.
+func WithReducer[T any](fn func(cur, incoming T) T) Option {
+	return func(c *config) {
+		c.reducer = fn
+	}
+}
+
+// reduce merges val into the latest value.
+// Caller must hold b.mu.
+func (b *Of[T]) reduce(val T) T {
+	if b.reducer == nil {
+		return val
+	}
+	latest := b.cur
+	if b.hasPending {
+		latest = b.pending
+	}
+	return b.reducer(latest, val)
+}
@@ -0,0 +1,46 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSetReducer(t *testing.T) {
+
+	union := func(cur, in map[string]bool) map[string]bool {
+		out := make(map[string]bool, len(cur)+len(in))
+		for k := range cur {
+			out[k] = true
+		}
+		for k := range in {
+			out[k] = true
+		}
+		return out
+	}
+	bc := bchan.NewOfWithOptions[map[string]bool](bchan.WithReducer(union))
+	bc.Bcast(map[string]bool{"a": true})
+	bc.Set(map[string]bool{"b": true})
+	bc.Bcast(map[string]bool{"c": true})
+	if got := <-bc.Ch; len(got) != 3 {
+		t.Fatalf("got %v, want the union of all three", got)
+	}
+	bc.BcastAck()
+
+	cur := bc.Get()
+	if !bc.CompareAndBcast(cur, map[string]bool{"z": true}) {
+		t.Fatal("CompareAndBcast failed")
+	}
+	if got := bc.Get(); len(got) != 1 || !got["z"] {
+		t.Fatalf("CompareAndBcast should replace outright, got %v", got)
+	}
+
+	counter := bchan.NewOf[int](1)
+	counter.SetReducer(func(cur, in int) int { return cur + in })
+	for i := 0; i < 5; i++ {
+		counter.Bcast(1)
+	}
+	if v := counter.Get(); v != 5 {
+		t.Fatalf("counter is %d, want 5", v)
+	}
+}
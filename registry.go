@@ -0,0 +1,120 @@
+package bchan
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrNameTaken is returned by Register when the
+// name is already registered.
+var ErrNameTaken = errors.New("bchan: name already registered")
+
+// Registry lets loosely coupled packages rendezvous
+// on Bchans by name instead of passing pointers
+// around. Any Bchan, of any value type, can be
+// registered; LookupOf and GetOrCreateOf fetch
+// typed ones. DefaultRegistry serves the whole
+// process; make more with NewRegistry to scope
+// names more narrowly.
+type Registry struct {
+	mu sync.Mutex
+	m  map[string]Member
+}
+
+// DefaultRegistry is the process-wide Registry
+// used by Register, Lookup, and GetOrCreate.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry makes an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{m: make(map[string]Member)}
+}
+
+// Register files b under name. It returns
+// ErrNameTaken if the name is in use.
+func (r *Registry) Register(name string, b Member) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.m[name]; ok {
+		return ErrNameTaken
+	}
+	r.m[name] = b
+	return nil
+}
+
+// Unregister removes name, if present.
+// The Bchan itself is left alone.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.m, name)
+}
+
+// Lookup returns the Bchan registered under name.
+func (r *Registry) Lookup(name string) (*Bchan, bool) {
+	return LookupOf[interface{}](r, name)
+}
+
+// GetOrCreate returns the Bchan registered under
+// name, first creating and registering one with
+// expectedDiameter if there is none.
+func (r *Registry) GetOrCreate(name string, expectedDiameter int) *Bchan {
+	return GetOrCreateOf[interface{}](r, name, expectedDiameter)
+}
+
+// Names lists the registered names, sorted.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.m))
+	for name := range r.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupOf returns the *Of[T] registered under
+// name in r. ok is false if there is none, or if
+// what is there carries some other type.
+func LookupOf[T any](r *Registry, name string) (b *Of[T], ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok = r.m[name].(*Of[T])
+	return
+}
+
+// GetOrCreateOf is the typed GetOrCreate. It panics
+// if name is registered to a Bchan of another type.
+func GetOrCreateOf[T any](r *Registry, name string, expectedDiameter int) *Of[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m, ok := r.m[name]; ok {
+		b, ok := m.(*Of[T])
+		if !ok {
+			var zero T
+			panic(fmt.Sprintf("bchan: %q is registered as a %T, not a Bchan of %T", name, m, zero))
+		}
+		return b
+	}
+	b := NewOf[T](expectedDiameter)
+	r.m[name] = b
+	return b
+}
+
+// Register files b under name in DefaultRegistry.
+func Register(name string, b Member) error {
+	return DefaultRegistry.Register(name, b)
+}
+
+// Lookup finds name in DefaultRegistry.
+func Lookup(name string) (*Bchan, bool) {
+	return DefaultRegistry.Lookup(name)
+}
+
+// GetOrCreate is DefaultRegistry.GetOrCreate.
+func GetOrCreate(name string, expectedDiameter int) *Bchan {
+	return DefaultRegistry.GetOrCreate(name, expectedDiameter)
+}
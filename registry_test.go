@@ -0,0 +1,40 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestRegistry(t *testing.T) {
+
+	bc := bchan.New(1)
+	if err := bchan.Register("registry_test.config", bc); err != nil {
+		t.Fatal(err)
+	}
+	if err := bchan.Register("registry_test.config", bc); err != bchan.ErrNameTaken {
+		t.Fatalf("expected ErrNameTaken, got %v", err)
+	}
+	if got, ok := bchan.Lookup("registry_test.config"); !ok || got != bc {
+		t.Fatal("Lookup should find the registered Bchan")
+	}
+	if bchan.GetOrCreate("registry_test.config", 5) != bc {
+		t.Fatal("GetOrCreate should return the existing Bchan")
+	}
+
+	r := bchan.NewRegistry()
+	epoch := bchan.GetOrCreateOf[int](r, "epoch", 2)
+	if got, ok := bchan.LookupOf[int](r, "epoch"); !ok || got != epoch {
+		t.Fatal("LookupOf should find the typed Bchan")
+	}
+	if _, ok := bchan.LookupOf[string](r, "epoch"); ok {
+		t.Fatal("LookupOf with the wrong type should fail")
+	}
+	if _, ok := bchan.Lookup("epoch"); ok {
+		t.Fatal("instance registries are separate from the default")
+	}
+	r.Unregister("epoch")
+	if len(r.Names()) != 0 {
+		t.Fatal("Unregister should remove the name")
+	}
+}
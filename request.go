@@ -0,0 +1,111 @@
+package bchan
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoRequest is returned by Reply when the Seq
+// given is not that of a BcastRequest still
+// collecting replies.
+var ErrNoRequest = errors.New("bchan: no request open for that Seq")
+
+// Reply is a receiver's answer to a BcastRequest.
+type Reply struct {
+	Seq uint64
+	Val interface{}
+	At  time.Time
+}
+
+// request is an open BcastRequest.
+type request struct {
+	seq     uint64
+	replies []Reply
+
+	// wake is closed, and replaced, on every
+	// reply and when the request ends.
+	wake chan struct{}
+}
+
+// BcastRequest turns a broadcast into scatter/gather:
+// it broadcasts val, as Bcast would but never
+// debounced, and then collects, for timeout, the
+// answers receivers post with Reply quoting its
+// sequence number (see Seq, or an envelope's Seq).
+// It returns the replies in the order they came. If
+// another value is broadcast first, it stops early
+// with the replies so far and ErrSuperseded; if b is
+// closed, with ErrClosed. Over the rate limit, or
+// held back by SetBackpressure, it broadcasts
+// nothing and returns ErrRateLimited or
+// ErrBackpressure.
+func (b *Of[T]) BcastRequest(val T, timeout time.Duration) ([]Reply, error) {
+	b.lockForBcast()
+	if !b.isOpenFor("BcastRequest") {
+		b.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if err := b.admitNow("BcastRequest", &val, true); err != nil {
+		b.mu.Unlock()
+		return nil, err
+	}
+	b.cancelPending()
+	b.store("BcastRequest", val)
+	req := &request{seq: b.seq, wake: make(chan struct{})}
+	b.req = req
+	b.activate()
+	b.mu.Unlock()
+
+	due, timer := b.timeout(timeout)
+	defer timer.Stop()
+	for {
+		b.mu.Lock()
+		wake := req.wake
+		switch {
+		case b.closed:
+			b.mu.Unlock()
+			return req.replies, ErrClosed
+		case b.req != req:
+			b.mu.Unlock()
+			return req.replies, ErrSuperseded
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-due:
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.req == req {
+				b.endRequest()
+			}
+			return req.replies, nil
+		}
+	}
+}
+
+// Reply posts v as an answer to the BcastRequest
+// whose value had sequence number seq. It returns
+// ErrNoRequest if that request has finished or
+// never was.
+func (b *Of[T]) Reply(seq uint64, v interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	req := b.req
+	if req == nil || req.seq != seq {
+		return ErrNoRequest
+	}
+	req.replies = append(req.replies, Reply{Seq: seq, Val: v, At: b.now()})
+	close(req.wake)
+	req.wake = make(chan struct{})
+	return nil
+}
+
+// endRequest closes any open request.
+// Caller must hold b.mu.
+func (b *Of[T]) endRequest() {
+	if b.req != nil {
+		close(b.req.wake)
+		b.req = nil
+	}
+}
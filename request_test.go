@@ -0,0 +1,56 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestBcastRequest(t *testing.T) {
+
+	bc := bchan.NewOf[string](3)
+	for i := 0; i < 3; i++ {
+		go func(i int) {
+			env := <-bc.Ch
+			seq := bc.Seq()
+			bc.BcastAck()
+			if env == "who is there?" {
+				bc.Reply(seq, i)
+			}
+		}(i)
+	}
+
+	replies, err := bc.BcastRequest("who is there?", 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replies) != 3 {
+		t.Fatalf("got %d replies, want 3: %v", len(replies), replies)
+	}
+	seen := make(map[interface{}]bool)
+	for _, r := range replies {
+		if r.Seq != 1 {
+			t.Fatalf("reply for seq %d, want 1", r.Seq)
+		}
+		seen[r.Val] = true
+	}
+	if len(seen) != 3 {
+		t.Fatalf("replies %v are not one per receiver", replies)
+	}
+	if err := bc.Reply(1, "late"); err != bchan.ErrNoRequest {
+		t.Fatalf("a late reply got %v, want ErrNoRequest", err)
+	}
+}
+
+func TestBcastRequestSuperseded(t *testing.T) {
+
+	bc := bchan.NewOf[string](1)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bc.Bcast("next")
+	}()
+	if _, err := bc.BcastRequest("q", 5*time.Second); err != bchan.ErrSuperseded {
+		t.Fatalf("got %v, want ErrSuperseded", err)
+	}
+}
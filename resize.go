@@ -0,0 +1,71 @@
+package bchan
+
+// Resize grows b to an expectedDiameter of n,
+// swapping in a larger buffered Ch while keeping
+// the current value and on/off state. Resize never
+// shrinks (see Shrink); if n is not bigger than
+// the present diameter it does nothing.
+//
+// The old Ch is retired, not dropped: receivers
+// already blocked on it still wake, and it goes on
+// being served for a while, so that they can move
+// over to b.Ch at their own pace; see Moved for how
+// they learn to. Reading the Ch field directly is
+// unsafe on a Bchan that is resized while in use,
+// since the read is unsynchronized: receive via
+// Recv, TryRecv or RecvCh, which pick up the new
+// channel safely. The old Ch stops being served
+// once it has gone a whole value without a receive,
+// and at once on a Bchan made WithAtMostOnce, whose
+// undelivered copies move to the new Ch instead, so
+// that none is handed out twice; either way it is
+// not closed until b is, so a receive left on it
+// blocks rather than returning zero values.
+// Subscriptions and consumer groups keep their own
+// channels throughout.
+// A Bchan made WithCondBackend has no buffer to
+// grow, and Resize leaves it be.
+func (b *Of[T]) Resize(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("Resize") {
+		return
+	}
+	if n+1 <= cap(b.Ch) || b.condBackend {
+		return
+	}
+	b.replaceCh(n)
+	b.logf("bchan: Resize diameter=%d", n)
+	b.emit("Resize")
+}
+
+// Shrink is Resize the other way: it cuts b down
+// to an expectedDiameter of n (at least 1), so that
+// after a scale-in b stops carrying a buffer sized
+// for its peak. If n is not smaller than the present
+// diameter it does nothing. The old Ch is retired
+// as for Resize, and its memory is let go once its
+// receivers have moved over to b.Ch.
+func (b *Of[T]) Shrink(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("Shrink") {
+		return
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n+1 >= cap(b.Ch) || b.condBackend {
+		return
+	}
+	b.replaceCh(n)
+	b.logf("bchan: Shrink diameter=%d", n)
+	b.emit("Shrink")
+}
+
+// ch returns the current Ch under lock.
+func (b *Of[T]) ch() chan T {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Ch
+}
@@ -0,0 +1,70 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestResize(t *testing.T) {
+
+	bc := bchan.New(1)
+	bc.Bcast("bill")
+	old := bc.Ch
+
+	bc.Resize(0) // never shrinks
+	if cap(bc.Ch) != 2 {
+		t.Fatalf("Resize smaller should be a no-op, cap %v", cap(bc.Ch))
+	}
+
+	bc.Resize(8)
+	if cap(bc.Ch) != 9 || len(bc.Ch) != 9 {
+		t.Fatalf("expected a full channel of cap 9, got len %v cap %v", len(bc.Ch), cap(bc.Ch))
+	}
+	if v := <-old; v != "bill" {
+		t.Fatalf("old channel should still deliver, got %v", v)
+	}
+	if v, err := bc.Recv(context.Background()); err != nil || v != "bill" {
+		t.Fatalf("expected bill, got %v, %v", v, err)
+	}
+
+	bc.Clear()
+	bc.Resize(10)
+	if len(bc.Ch) != 0 {
+		t.Fatal("Resize while off should not fill")
+	}
+}
+
+func TestShrink(t *testing.T) {
+
+	bc := bchan.New(8)
+	defer bc.Close()
+	bc.Bcast("bill")
+	old := bc.Ch
+
+	bc.Shrink(8)
+	if bc.Ch != old {
+		t.Fatal("Shrink to the same size should be a no-op")
+	}
+	bc.Shrink(2)
+	if cap(bc.Ch) != 3 || len(bc.Ch) != 3 {
+		t.Fatalf("expected a full channel of cap 3, got len %v cap %v", len(bc.Ch), cap(bc.Ch))
+	}
+	if len(old) != 1 {
+		t.Fatalf("the old Ch should be left one copy, has %d", len(old))
+	}
+	bc.Shrink(0)
+	if cap(bc.Ch) != 2 {
+		t.Fatalf("Shrink goes no lower than a diameter of 1, cap %v", cap(bc.Ch))
+	}
+	if v, err := bc.Recv(context.Background()); err != nil || v != "bill" {
+		t.Fatalf("expected bill, got %v, %v", v, err)
+	}
+
+	// nobody used old, so the next value lets it go.
+	bc.Bcast("ted")
+	if len(old) != 0 {
+		t.Fatalf("the big Ch should have been released, holds %d", len(old))
+	}
+}
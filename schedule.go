@@ -0,0 +1,39 @@
+package bchan
+
+import (
+	"time"
+)
+
+// Scheduled is a handle on a broadcast staged
+// by BcastAt or BcastAfter.
+type Scheduled struct {
+	timer Timer
+}
+
+// Cancel stops the staged broadcast, reporting
+// false if it already happened (or was cancelled).
+func (s *Scheduled) Cancel() bool {
+	return s.timer.Stop()
+}
+
+// BcastAfter arranges for Bcast(val) to happen once
+// d has passed, so producers can stage a future
+// state change, such as a planned maintenance
+// flag, without a timer goroutine of their own.
+// If b is closed by then, nothing happens.
+func (b *Of[T]) BcastAfter(d time.Duration, val T) *Scheduled {
+	return &Scheduled{timer: b.afterFunc(d, func() {
+		b.lockForBcast()
+		defer b.mu.Unlock()
+		if b.closed {
+			return
+		}
+		b.bcast("BcastAfter", val)
+	})}
+}
+
+// BcastAt is BcastAfter for a point in time.
+// A time already past means as soon as possible.
+func (b *Of[T]) BcastAt(t time.Time, val T) *Scheduled {
+	return b.BcastAfter(t.Sub(b.now()), val)
+}
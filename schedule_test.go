@@ -0,0 +1,28 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestBcastAfter(t *testing.T) {
+
+	bc := bchan.NewOf[string](1)
+	bc.Bcast("normal")
+	bc.BcastAt(time.Now().Add(20*time.Millisecond), "maintenance")
+	cancelled := bc.BcastAfter(10*time.Millisecond, "never")
+	if !cancelled.Cancel() {
+		t.Fatal("Cancel before firing should report true")
+	}
+
+	if bc.Get() != "normal" {
+		t.Fatal("staged broadcast went out early")
+	}
+	eventually(t, "staged broadcast", func() bool { return bc.Get() == "maintenance" })
+	time.Sleep(20 * time.Millisecond)
+	if bc.Get() != "maintenance" {
+		t.Fatal("cancelled broadcast went out")
+	}
+}
@@ -0,0 +1,38 @@
+package bchan
+
+import (
+	"os"
+	"os/signal"
+)
+
+// FromSignals returns a Bchan that broadcasts
+// each incoming OS signal in sigs (all signals,
+// if none are given) as an os.Signal value.
+// signal.Notify hands each signal to just one
+// receiver of its channel; here every goroutine
+// watching Ch sees it. Close the Bchan to stop
+// relaying and undo the signal.Notify.
+func FromSignals(diameter int, sigs ...os.Signal) *Bchan {
+	b := New(diameter)
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sigs...)
+	w, _ := b.watch()
+	go func() {
+		defer signal.Stop(c)
+		for {
+			select {
+			case s := <-c:
+				b.lockForBcast()
+				if !b.closed {
+					b.bcast("Bcast", s)
+				}
+				b.mu.Unlock()
+			case _, ok := <-w:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return b
+}
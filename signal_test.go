@@ -0,0 +1,37 @@
+//go:build unix
+
+package bchan_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestFromSignals(t *testing.T) {
+
+	bc := bchan.FromSignals(2, syscall.SIGUSR1)
+	defer bc.Close()
+
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Signal(syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case v := <-bc.Ch:
+			if v != syscall.SIGUSR1 {
+				t.Fatalf("got %v, want SIGUSR1", v)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("receiver %d never saw the signal", i)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package bchan
+
+// SignalBchan is a Bchan for pure wake-ups and
+// level signals, where only whether it is on
+// matters. Its payload is struct{}, so Ch's slots
+// take no memory and nothing is ever boxed.
+// Receive from Ch and BcastAck as usual; every
+// other Bchan method is available too.
+type SignalBchan struct {
+	*Of[struct{}]
+}
+
+// NewSignal makes a SignalBchan, lowered. See New
+// for the meaning of expectedDiameter.
+func NewSignal(expectedDiameter int) *SignalBchan {
+	return &SignalBchan{NewOf[struct{}](expectedDiameter)}
+}
+
+// Raise starts signalling: receives on Ch succeed
+// until Lower. Each Raise counts as a new value
+// (see Seq), so it wakes a Next or RecvAfter even
+// if s was raised already.
+func (s *SignalBchan) Raise() {
+	s.Bcast(struct{}{})
+}
+
+// Lower stops signalling; receives on Ch block.
+func (s *SignalBchan) Lower() {
+	s.Off()
+}
+
+// Raised reports whether s is signalling.
+func (s *SignalBchan) Raised() bool {
+	return s.IsOn()
+}
@@ -0,0 +1,56 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSignalBchan(t *testing.T) {
+
+	s := bchan.NewSignal(2)
+	select {
+	case <-s.Ch:
+		t.Fatal("a new SignalBchan should be lowered")
+	default:
+	}
+
+	s.Raise()
+	if !s.Raised() {
+		t.Fatal("Raise did not raise")
+	}
+	for i := 0; i < 5; i++ {
+		<-s.Ch
+		s.BcastAck()
+	}
+
+	s.Lower()
+	if s.Raised() {
+		t.Fatal("Lower did not lower")
+	}
+	select {
+	case <-s.Ch:
+		t.Fatal("receive succeeded while lowered")
+	default:
+	}
+}
+
+func TestSignalBchanAllocs(t *testing.T) {
+
+	s := bchan.NewSignal(1)
+	s.Raise()
+	allocs := testing.AllocsPerRun(100, func() {
+		<-s.Ch
+		s.BcastAck()
+	})
+	if allocs != 0 {
+		t.Fatalf("receive and ack allocated %v times", allocs)
+	}
+	allocs = testing.AllocsPerRun(100, func() {
+		s.Raise()
+		s.Lower()
+	})
+	if allocs != 0 {
+		t.Fatalf("Raise and Lower allocated %v times", allocs)
+	}
+}
@@ -0,0 +1,78 @@
+package bchan
+
+import (
+	"time"
+)
+
+// snapshot is the persisted form of a Bchan.
+type snapshot[T any] struct {
+	Val  T
+	Seq  uint64
+	On   bool
+	At   time.Time
+	Meta Meta
+}
+
+// MarshalBinary captures b's current value, whether
+// it is broadcasting, its sequence number, set time
+// and Meta, encoded with b's Codec (see SetCodec),
+// which defaults to GobCodec. Write it out
+// before shutting down and hand it to
+// UnmarshalBinary on restart to resume where b left
+// off. A plain Bchan's values must be of types
+// registered with gob.Register.
+func (b *Of[T]) MarshalBinary() ([]byte, error) {
+	b.mu.Lock()
+	s := snapshot[T]{Val: b.cur, Seq: b.seq, On: b.on, At: b.setAt, Meta: b.meta}
+	c := b.codecOr(GobCodec)
+	b.mu.Unlock()
+	return Marshal(c, &s)
+}
+
+// UnmarshalBinary restores state saved by
+// MarshalBinary into b, typically a freshly made
+// one: the value, set time, Meta and sequence number
+// are put back, and if broadcasting was on it is on
+// again straight away, with Ch filled. Sequence
+// numbers carry on from the saved one, so receivers
+// comparing Seq across the restart see no rewind.
+func (b *Of[T]) UnmarshalBinary(data []byte) error {
+	b.mu.Lock()
+	c := b.codecOr(GobCodec)
+	b.mu.Unlock()
+	var s snapshot[T]
+	if err := Unmarshal(c, data, &s); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("UnmarshalBinary") {
+		return ErrClosed
+	}
+	if err := b.admitWhole("UnmarshalBinary", &s.Val); err != nil {
+		return err
+	}
+	b.cancelPending()
+	b.stopTTL()
+	b.seq = s.Seq
+	b.checkedSeq = 0
+	b.seqAcks.Store(0)
+	clear(b.seqAckers)
+	b.seqFills = 0
+	b.quorum = nil
+	b.setAt = s.At
+	b.freshAt = b.now()
+	b.cur = s.Val
+	b.meta = s.Meta
+	b.remember(s.Val)
+	b.kickIdle()
+	b.logf("bchan: restored seq=%d on=%v", b.seq, s.On)
+	if s.On {
+		b.activate()
+		return nil
+	}
+	b.turnOff()
+	b.drain()
+	b.publish(false)
+	return nil
+}
@@ -0,0 +1,44 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSnapshot(t *testing.T) {
+
+	bc := bchan.NewOf[string](1)
+	bc.Bcast("v1")
+	bc.BcastMeta("v2", bchan.Meta{Source: "primary"})
+	data, err := bc.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := bchan.NewOf[string](1)
+	if err := restarted.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if v := <-restarted.Ch; v != "v2" {
+		t.Fatalf("restored Bchan broadcast %q, want v2", v)
+	}
+	restarted.BcastAck()
+	env := restarted.Envelope()
+	if env.Seq != 2 || env.Meta.Source != "primary" {
+		t.Fatalf("restored envelope %+v, want seq 2 from primary", env)
+	}
+
+	// off stays off.
+	bc.Off()
+	if data, err = bc.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	restarted = bchan.NewOf[string](1)
+	if err := restarted.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if v, on := restarted.Cur(); on || v != "v2" {
+		t.Fatalf("got %q on=%v, want v2 held back", v, on)
+	}
+}
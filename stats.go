@@ -0,0 +1,96 @@
+package bchan
+
+import (
+	"time"
+)
+
+// Stats is a point-in-time report on a Bchan,
+// meant for debugging stuck consumers.
+type Stats struct {
+	// Fills counts values put into Ch
+	// (and any consumer group channels).
+	Fills uint64
+
+	// Drains counts stale values removed
+	// from those channels by Set, Bcast,
+	// Clear, and Close.
+	Drains uint64
+
+	// Acks counts BcastAck calls.
+	Acks uint64
+
+	// AckViolations counts breaches of the ack
+	// rule reported under CheckAcks.
+	AckViolations uint64
+
+	// SlowAcks counts receives acked later
+	// than the SetAckDeadline deadline.
+	SlowAcks uint64
+
+	// Len and Cap are the current occupancy
+	// and capacity of Ch. A Len stuck at 0
+	// while On is true means receivers are
+	// not acking.
+	Len int
+	Cap int
+
+	// On is whether broadcasting is on.
+	On bool
+
+	// Seq is the sequence number of the
+	// current value, which is also the number
+	// of Set and Bcast calls so far.
+	Seq uint64
+
+	// Age is how long ago the current value
+	// was Set or Bcast; zero if never.
+	Age time.Duration
+
+	// Receivers and Subscriptions count the
+	// registered receivers (see RegisterReceiver)
+	// and the subscriptions b has now.
+	Receivers     int
+	Subscriptions int
+}
+
+// Stats returns a snapshot of b's counters and state.
+func (b *Of[T]) Stats() Stats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	st := Stats{
+		Fills:  b.nfill.Load(),
+		Drains: b.ndrain.Load(),
+		Acks:   b.nack.Load(),
+		Len:    len(b.Ch),
+		Cap:    cap(b.Ch),
+		On:     b.on,
+		Seq:    b.seq,
+	}
+	st.AckViolations = b.nviolations
+	st.SlowAcks = b.nslow
+	st.Receivers = len(b.receivers)
+	st.Subscriptions = len(b.subs)
+	if !b.setAt.IsZero() {
+		st.Age = b.now().Sub(b.setAt)
+	}
+	return st
+}
+
+// ObserveAcks arranges for f to be called after
+// every BcastAck with how long the ack took,
+// including any wait for b's lock; this is the
+// refill latency seen by receivers. f runs with
+// b locked, so it must be quick and must not
+// call back into b. Pass nil to stop.
+func (b *Of[T]) ObserveAcks(f func(d time.Duration)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ackObs = f
+	b.hasAckObs.Store(f != nil)
+}
+
+// observingAcks is a lock-free peek, so that
+// acks only read the clock when someone cares.
+func (b *Of[T]) observingAcks() bool {
+	return b.hasAckObs.Load()
+}
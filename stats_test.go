@@ -0,0 +1,63 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestStats(t *testing.T) {
+
+	bc := bchan.New(2)
+	if st := bc.Stats(); st.Fills != 0 || st.On || st.Age != 0 || st.Cap != 3 {
+		t.Fatalf("unexpected fresh stats %+v", st)
+	}
+
+	bc.Bcast("bill")
+	<-bc.Ch
+	bc.BcastAck()
+	<-bc.Ch // and forget to ack
+
+	st := bc.Stats()
+	if st.Fills != 4 || st.Acks != 1 || st.Len != 2 || !st.On || st.Age <= 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+
+	bc.Clear()
+	if st := bc.Stats(); st.Drains != 2 || st.Len != 0 || st.On {
+		t.Fatalf("unexpected stats after Clear %+v", st)
+	}
+}
+
+func TestObserveAcks(t *testing.T) {
+
+	bc := bchan.New(1)
+	var n int
+	bc.ObserveAcks(func(d time.Duration) { n++ })
+	bc.Bcast("bill")
+	<-bc.Ch
+	bc.BcastAck()
+	bc.ObserveAcks(nil)
+	<-bc.Ch
+	bc.BcastAck()
+	if n != 1 {
+		t.Fatalf("expected exactly one observed ack, got %v", n)
+	}
+}
+
+func TestStatsConsumers(t *testing.T) {
+
+	bc := bchan.New(1)
+	defer bc.Close()
+	bc.RegisterReceiver("worker")
+	sub := bc.Subscribe()
+	st := bc.Stats()
+	if st.Receivers != 1 || st.Subscriptions != 1 {
+		t.Fatalf("unexpected consumer counts %+v", st)
+	}
+	bc.Unsubscribe(sub)
+	if st = bc.Stats(); st.Subscriptions != 0 {
+		t.Fatalf("Unsubscribe not counted: %+v", st)
+	}
+}
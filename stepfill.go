@@ -0,0 +1,45 @@
+package bchan
+
+// WithStepFills makes a Bchan that never fills Ch
+// by itself: the copies of the value that On, Bcast
+// or BcastAck would have put there are instead owed
+// (see FillsOwed) until StepFill hands them over. It
+// lets a simulation (see bchantest.Sim) choose when
+// fills happen relative to everything else, to
+// explore each interleaving in turn. Consumer group
+// channels and subscriptions fill as usual.
+func WithStepFills() Option {
+	return func(c *config) {
+		c.stepFills = true
+	}
+}
+
+// FillsOwed is how many copies of the current value
+// Ch is short of, on a Bchan made WithStepFills;
+// otherwise it is 0.
+func (b *Of[T]) FillsOwed() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if !b.owesFills() {
+		return 0
+	}
+	return min(b.owed, cap(b.Ch)-len(b.Ch))
+}
+
+// StepFill, on a Bchan made WithStepFills, puts one
+// owed copy of the current value into Ch, reporting
+// whether there was one to put.
+func (b *Of[T]) StepFill() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.owesFills() || !b.fillOne(b.Ch) {
+		return false
+	}
+	b.owed--
+	return true
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) owesFills() bool {
+	return b.stepFills && b.owed > 0 && !b.closed && b.on && b.live && !b.paused
+}
@@ -0,0 +1,37 @@
+package bchan_test
+
+import (
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestStepFills(t *testing.T) {
+
+	bc := bchan.NewOfWithOptions[int](bchan.WithDiameter(2), bchan.WithStepFills())
+	defer bc.Close()
+	bc.Bcast(1)
+	if len(bc.Ch) != 0 || bc.FillsOwed() != 3 {
+		t.Fatalf("Bcast should owe 3 fills, not make them; len=%d owed=%d", len(bc.Ch), bc.FillsOwed())
+	}
+	for bc.StepFill() {
+	}
+	if len(bc.Ch) != 3 || bc.FillsOwed() != 0 {
+		t.Fatalf("expected Ch full; len=%d owed=%d", len(bc.Ch), bc.FillsOwed())
+	}
+
+	<-bc.Ch
+	<-bc.Ch
+	if bc.FillsOwed() != 0 {
+		t.Fatal("a receive alone must not owe a fill")
+	}
+	bc.BcastAck()
+	if bc.FillsOwed() != 2 {
+		t.Fatalf("the ack should owe a top up of 2, got %d", bc.FillsOwed())
+	}
+
+	bc.Off()
+	if bc.FillsOwed() != 0 || bc.StepFill() {
+		t.Fatal("nothing is owed while off")
+	}
+}
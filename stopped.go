@@ -0,0 +1,52 @@
+package bchan
+
+// closedCh is returned by Stopped when
+// broadcasting is already off.
+var closedCh = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// Stopped returns a channel that is closed when
+// broadcasting stops, by Off, Clear, or Close, so
+// a receiver can tell "broadcasting stopped" apart
+// from "nothing yet" instead of blocking forever:
+//
+//	select {
+//	case v := <-b.Ch:
+//		b.BcastAck()
+//		...
+//	case <-b.Stopped():
+//		...
+//	}
+//
+// If broadcasting is off already, the returned
+// channel is closed. Call Stopped afresh after
+// each stop, as a later On starts a new run.
+func (b *Of[T]) Stopped() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.on {
+		return closedCh
+	}
+	if b.stopped == nil {
+		b.stopped = make(chan struct{})
+	}
+	return b.stopped
+}
+
+// turnOff clears the on flag and notifies
+// Stopped() waiters. Caller must hold b.mu.
+func (b *Of[T]) turnOff() {
+	b.on = false
+	if b.stopped != nil {
+		close(b.stopped)
+		b.stopped = nil
+	}
+}
+
+// Stopped is s's Bchan's Stopped.
+func (s *SubscriptionOf[T]) Stopped() <-chan struct{} {
+	return s.b.Stopped()
+}
@@ -0,0 +1,57 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestStopped(t *testing.T) {
+
+	bc := bchan.New(1)
+	select {
+	case <-bc.Stopped():
+	default:
+		t.Fatal("a Bchan that is off should report Stopped at once")
+	}
+
+	bc.Bcast("bill")
+	stopped := bc.Stopped()
+	select {
+	case <-stopped:
+		t.Fatal("Stopped while on")
+	default:
+	}
+
+	sub := bc.Subscribe()
+	defer bc.Unsubscribe(sub)
+	done := make(chan bool)
+	go func() {
+		<-sub.Ch
+		// keep receiving until told broadcasting stopped.
+		for {
+			select {
+			case <-sub.Ch:
+			case <-sub.Stopped():
+				close(done)
+				return
+			}
+		}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	bc.Off()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("receiver was never told of Off")
+	}
+	if bc.Get() != "bill" {
+		t.Fatal("Off should keep the value")
+	}
+	bc.On()
+	if v := <-bc.Ch; v != "bill" {
+		t.Fatalf("On after Off should resume bill, got %v", v)
+	}
+}
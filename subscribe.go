@@ -0,0 +1,404 @@
+package bchan
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Subscription is a private receive channel on a Bchan.
+// See SubscriptionOf.
+type Subscription = SubscriptionOf[interface{}]
+
+// SubscriptionOf gives one subscriber its own
+// receive-only channel Ch. Unlike the shared
+// Bchan.Ch, there is no ack rule: an internal
+// goroutine keeps Ch stocked with the current
+// value for as long as broadcasting is on.
+// A subscriber that misbehaves only ever
+// starves itself.
+type SubscriptionOf[T any] struct {
+	Ch <-chan T
+	ch chan T
+
+	// update hands new state to the
+	// delivery goroutine synchronously, so
+	// once Bcast returns no receive on Ch
+	// can observe the old value.
+	update chan subState[T]
+	done   chan struct{}
+
+	filter   func(T) bool
+	mapper   func(T) T
+	queue    int
+	conflate func(T) interface{}
+	skipCur  bool
+
+	b *Of[T]
+
+	// lastSeq is the Seq of the value last
+	// received from Ch, for Lag.
+	lastSeq atomic.Uint64
+
+	// counters for Received and Dropped.
+	nrecv atomic.Uint64
+	ndrop atomic.Uint64
+}
+
+type subState[T any] struct {
+	val  T
+	seq  uint64
+	live bool
+	q    *Quorum
+
+	// out, if set, is applied to each value sent;
+	// see Of.handout.
+	out func(v T) T
+
+	// pulse, if not nil, marks a one-shot
+	// Pulse of val rather than new state; the
+	// outcome is reported back on it.
+	pulse chan bool
+}
+
+// SubOption configures a subscription; pass
+// them to Subscribe.
+type SubOption func(c *subConfig)
+
+type subConfig struct {
+	backlog  int
+	queue    int
+	conflate interface{}
+	filter   interface{}
+	mapper   interface{}
+	skipCur  bool
+}
+
+// WithBacklog asks that a new subscription
+// first be sent, once each and oldest first,
+// up to n values from the Bchan's history
+// (see KeepHistory) before it switches over
+// to the current value. This lets a late
+// joiner catch up on intermediate updates.
+func WithBacklog(n int) SubOption {
+	return func(c *subConfig) {
+		c.backlog = n
+	}
+}
+
+// WithFilter gives a subscription a predicate:
+// only values for which keep returns true are
+// delivered, and the subscriber is not woken at
+// all for the rest. keep must be a func(T) bool
+// for the T of the Bchan subscribed to (so a
+// func(interface{}) bool for a plain Bchan).
+// It runs on the subscription's own goroutine,
+// once per new value. Every Set, Bcast or Pulse
+// hands its value to each subscription with b
+// locked, and must wait for a keep still running
+// on the last value; so keep should be quick, and
+// must not call b's methods, which could deadlock.
+func WithFilter[T any](keep func(v T) bool) SubOption {
+	return func(c *subConfig) {
+		c.filter = keep
+	}
+}
+
+// WithMap gives a subscription a transform:
+// each value is passed through fn before it is
+// delivered, after any WithFilter. Use it to hand
+// a consumer just the sliver of a bulky value it
+// needs. As with WithFilter, fn must be typed for
+// the Bchan's T, and it runs once per new value
+// on the subscription's goroutine, holding up
+// b's broadcasts just as keep does; so it too
+// should be quick, and must not call b's methods.
+func WithMap[T any](fn func(v T) T) SubOption {
+	return func(c *subConfig) {
+		c.mapper = fn
+	}
+}
+
+// Subscribe returns a new Subscription whose
+// Ch delivers the current value whenever
+// broadcasting is on. Call Unsubscribe when
+// done with it to release the delivery goroutine.
+func (b *Of[T]) Subscribe(opts ...SubOption) *SubscriptionOf[T] {
+	cfg := subOptions(opts)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subscribe(cfg)
+}
+
+func subOptions(opts []SubOption) (cfg subConfig) {
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return cfg
+}
+
+// caller must hold b.mu.
+func (b *Of[T]) subscribe(cfg subConfig) *SubscriptionOf[T] {
+	ch := make(chan T)
+	s := &SubscriptionOf[T]{
+		Ch:       ch,
+		ch:       ch,
+		update:   make(chan subState[T]),
+		done:     make(chan struct{}),
+		filter:   optFunc[func(T) bool](cfg.filter, "WithFilter"),
+		mapper:   optFunc[func(T) T](cfg.mapper, "WithMap"),
+		queue:    cfg.queue,
+		conflate: optFunc[func(T) interface{}](cfg.conflate, "WithConflate"),
+		skipCur:  cfg.skipCur,
+		b:        b,
+	}
+	if b.subs == nil {
+		b.subs = make(map[*SubscriptionOf[T]]struct{})
+	}
+	b.subs[s] = struct{}{}
+	backlog := b.hist.last(cfg.backlog)
+	if s.queue > 0 {
+		go s.deliverQueued(subState[T]{val: b.cur, seq: b.seq, live: b.live && !b.paused, out: b.out}, backlog)
+		return s
+	}
+	go s.deliver(subState[T]{val: b.cur, seq: b.seq, live: b.live && !b.paused, out: b.out}, backlog)
+	return s
+}
+
+// Unsubscribe stops delivery to s and closes s.Ch.
+// It is safe to call more than once.
+func (b *Of[T]) Unsubscribe(s *SubscriptionOf[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[s]; !ok {
+		return
+	}
+	delete(b.subs, s)
+	close(s.done)
+}
+
+// publish pushes the current value to every
+// subscription. live says whether the value
+// should be delivered or held back.
+// Caller must hold b.mu.
+func (b *Of[T]) publish(live bool) {
+	b.live = live
+	b.record()
+	b.resolveFutures(live && !b.paused)
+	b.feedExact(live && !b.paused)
+	if b.cond != nil {
+		b.cond.Broadcast()
+	}
+	st := subState[T]{val: b.cur, seq: b.seq, live: live && !b.paused, q: b.quorum, out: b.out}
+	for s := range b.subs {
+		s.update <- st
+	}
+	for w := range b.watchers {
+		select {
+		case w <- struct{}{}:
+		default:
+		}
+	}
+	b.verify()
+}
+
+// watch returns a channel that is signalled,
+// coalescing, after every change of state, so
+// that package-owned goroutines can follow b
+// without taking part in any ack protocol.
+// It is closed when b is. Call the returned
+// func to stop watching.
+func (b *Of[T]) watch() (<-chan struct{}, func()) {
+	w := make(chan struct{}, 1)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(w)
+		return w, func() {}
+	}
+	if b.watchers == nil {
+		b.watchers = make(map[chan struct{}]bool)
+	}
+	b.watchers[w] = true
+	return w, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.watchers, w)
+	}
+}
+
+// deliver is the per-subscription goroutine.
+// Any backlog goes out first, one send per value.
+func (s *SubscriptionOf[T]) deliver(st subState[T], backlog []T) {
+	defer close(s.ch)
+	s.b.labelGoroutine()
+	backlog = s.prepBacklog(backlog)
+	pass := s.wants(st)
+	val := s.xform(st.val, pass)
+	sent := false
+	for {
+		var out chan T
+		next := val
+		if len(backlog) > 0 {
+			out, next = s.ch, backlog[0]
+		} else if pass {
+			out = s.ch
+		}
+		if out != nil {
+			next = st.handout(next)
+		}
+		select {
+		case out <- next:
+			if len(backlog) > 0 {
+				backlog = backlog[1:]
+				break
+			}
+			s.lastSeq.Store(st.seq)
+			s.nrecv.Add(1)
+			sent = true
+			if st.q != nil {
+				st.q.confirm(s)
+				st.q = nil
+			}
+		case nst := <-s.update:
+			if nst.pulse != nil {
+				s.pulse(nst)
+				continue
+			}
+			if nst.seq != st.seq {
+				if pass && !sent {
+					// overwritten before we got it out.
+					s.ndrop.Add(1)
+				}
+				sent = false
+			}
+			st = nst
+			pass = s.wants(st)
+			val = s.xform(st.val, pass)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// prepBacklog filters and maps backlog for s.
+func (s *SubscriptionOf[T]) prepBacklog(backlog []T) []T {
+	if s.filter != nil {
+		kept := backlog[:0]
+		for _, v := range backlog {
+			if s.filter(v) {
+				kept = append(kept, v)
+			}
+		}
+		backlog = kept
+	}
+	if s.mapper != nil {
+		for i, v := range backlog {
+			backlog[i] = s.mapper(v)
+		}
+	}
+	return backlog
+}
+
+// pulse offers a Pulse to the subscriber. The send
+// is unbuffered, so this only succeeds if the
+// subscriber is waiting now.
+func (s *SubscriptionOf[T]) pulse(st subState[T]) {
+	if !s.wants(st) {
+		st.pulse <- false
+		return
+	}
+	select {
+	case s.ch <- st.handout(s.xform(st.val, true)):
+		st.pulse <- true
+	default:
+		st.pulse <- false
+	}
+}
+
+// handout returns what the subscriber
+// should be given for v.
+func (st subState[T]) handout(v T) T {
+	if st.out == nil {
+		return v
+	}
+	return st.out(v)
+}
+
+// xform applies s's mapper, if any, to a
+// value that is going to be delivered.
+func (s *SubscriptionOf[T]) xform(v T, deliverable bool) T {
+	if s.mapper != nil && deliverable {
+		return s.mapper(v)
+	}
+	return v
+}
+
+// wants says whether st is deliverable to s.
+func (s *SubscriptionOf[T]) wants(st subState[T]) bool {
+	if !st.live && st.pulse == nil {
+		return false
+	}
+	return s.filter == nil || s.filter(st.val)
+}
+
+// optFunc recovers a typed func stashed in an
+// option as an interface{}, panicking helpfully
+// if it was written for a different T.
+func optFunc[F any](f interface{}, opt string) F {
+	var zero F
+	if f == nil {
+		return zero
+	}
+	typed, ok := f.(F)
+	if !ok {
+		panic(fmt.Sprintf("bchan: %s given a %T; this Bchan needs a %T", opt, f, zero))
+	}
+	return typed
+}
+
+// LastSeq is the sequence number (see Seq) of the
+// value this subscription most recently received,
+// or 0 if it has received nothing yet.
+//
+// It is updated by the delivery goroutine just
+// after each hand-off, so a read made immediately
+// after a receive may briefly show the previous
+// number. Where every value must be matched to its
+// epoch exactly, broadcast envelopes instead (see
+// NewEnveloped); their Seq travels with the value.
+func (s *SubscriptionOf[T]) LastSeq() uint64 {
+	return s.lastSeq.Load()
+}
+
+// Lag is how many broadcasts (Set or Bcast) have
+// happened since the one whose value this
+// subscription last received. A slow consumer
+// that sees Lag > 1 after handling a value
+// knows it skipped intermediate states and can
+// trigger a full resync.
+func (s *SubscriptionOf[T]) Lag() uint64 {
+	cur := s.b.Seq()
+	last := s.lastSeq.Load()
+	if last >= cur {
+		return 0
+	}
+	return cur - last
+}
+
+// Received counts the values this subscription
+// has taken from Ch, repeats included.
+func (s *SubscriptionOf[T]) Received() uint64 {
+	return s.nrecv.Load()
+}
+
+// Dropped counts the values this subscription
+// would have been given but never got, because a
+// newer Set or Bcast replaced them before the
+// subscriber came back for more. A steadily rising
+// count marks a chronically slow consumer. For a
+// WithQueue subscription, it counts values pushed
+// out of a full queue. Values rejected by WithFilter
+// are not counted.
+func (s *SubscriptionOf[T]) Dropped() uint64 {
+	return s.ndrop.Load()
+}
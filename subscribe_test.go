@@ -0,0 +1,153 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSubscribe(t *testing.T) {
+
+	bc := bchan.New(1)
+	s1 := bc.Subscribe()
+	s2 := bc.Subscribe()
+
+	select {
+	case <-s1.Ch:
+		t.Fatal("nothing broadcast yet; s1 should have blocked")
+	case <-time.After(10 * time.Millisecond):
+		// ok, good.
+	}
+
+	bc.Bcast("bill")
+
+	// no acks, and yet every subscriber keeps receiving.
+	for i := 0; i < 3; i++ {
+		for _, s := range []*bchan.Subscription{s1, s2} {
+			select {
+			case v := <-s.Ch:
+				if v != "bill" {
+					t.Fatalf("expected bill, got %v", v)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("subscription should have been refilled")
+			}
+		}
+	}
+
+	bc.Bcast("lyle")
+	if v := <-s1.Ch; v != "lyle" {
+		t.Fatalf("after Bcast returns, expected lyle, got %v", v)
+	}
+
+	bc.Unsubscribe(s1)
+	bc.Unsubscribe(s1)
+	for range s1.Ch {
+		// drain until closed
+	}
+	if v := <-s2.Ch; v != "lyle" {
+		t.Fatalf("s2 should be unaffected by s1 leaving, got %v", v)
+	}
+
+	bc.Clear()
+	select {
+	case <-s2.Ch:
+		t.Fatal("Clear() means receive should have blocked.")
+	case <-time.After(10 * time.Millisecond):
+		// ok, good.
+	}
+	bc.Unsubscribe(s2)
+}
+
+func TestSubscribeWithFilter(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	evens := bc.Subscribe(bchan.WithFilter(func(v int) bool { return v%2 == 0 }))
+	defer bc.Unsubscribe(evens)
+
+	bc.Bcast(1)
+	select {
+	case v := <-evens.Ch:
+		t.Fatalf("odd value %v should have been filtered", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	bc.Bcast(2)
+	if v := <-evens.Ch; v != 2 {
+		t.Fatalf("expected 2, got %v", v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("filter for the wrong type should panic")
+		}
+	}()
+	bc.Subscribe(bchan.WithFilter(func(v string) bool { return true }))
+}
+
+func TestSubscribeWithMap(t *testing.T) {
+
+	type config struct {
+		Name  string
+		Bulky [1 << 10]byte
+	}
+	bc := bchan.New(1)
+	names := bc.Subscribe(bchan.WithMap(func(v interface{}) interface{} {
+		return v.(config).Name
+	}))
+	defer bc.Unsubscribe(names)
+
+	bc.Bcast(config{Name: "prod"})
+	if v := <-names.Ch; v != "prod" {
+		t.Fatalf("expected the projected name, got %v", v)
+	}
+}
+
+func TestSubscriptionLag(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	sub := bc.Subscribe()
+	defer bc.Unsubscribe(sub)
+
+	bc.Bcast(1)
+	<-sub.Ch
+	<-sub.Ch // the second receive guarantees the first was recorded
+	if sub.LastSeq() != 1 || sub.Lag() != 0 {
+		t.Fatalf("expected caught up at seq 1, got last %v lag %v", sub.LastSeq(), sub.Lag())
+	}
+
+	// a slow consumer misses 2 and 3.
+	bc.Bcast(2)
+	bc.Bcast(3)
+	bc.Bcast(4)
+	if lag := sub.Lag(); lag != 3 {
+		t.Fatalf("expected lag 3 before receiving, got %v", lag)
+	}
+	<-sub.Ch
+	<-sub.Ch
+	if sub.LastSeq() != 4 || sub.Lag() != 0 {
+		t.Fatalf("expected caught up at seq 4, got last %v lag %v", sub.LastSeq(), sub.Lag())
+	}
+}
+
+func TestSubscriptionDropped(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	sub := bc.Subscribe()
+	defer bc.Unsubscribe(sub)
+
+	bc.Bcast(1)
+	<-sub.Ch
+	bc.Bcast(2) // never received...
+	bc.Bcast(3) // ...nor this
+	bc.Bcast(4)
+	<-sub.Ch
+	<-sub.Ch
+	if d := sub.Dropped(); d != 2 {
+		t.Fatalf("expected 2 dropped, got %v", d)
+	}
+	if r := sub.Received(); r < 2 {
+		t.Fatalf("expected at least 2 received, got %v", r)
+	}
+}
@@ -0,0 +1,41 @@
+package bchan
+
+import (
+	"context"
+)
+
+// ToChan returns a plain receive-only channel
+// that behaves like Ch without the ack rule, for
+// handing to code that only knows channels: while
+// broadcasting is on, every receive yields the
+// current value, and while off, receives block.
+// A goroutine does the receiving from Ch and the
+// BcastAck; a value it holds that is replaced
+// before being taken is dropped for the new one.
+// The channel is closed when ctx is done or b is
+// closed.
+func (b *Of[T]) ToChan(ctx context.Context) <-chan T {
+	out := make(chan T)
+	wake, cancel := b.watch()
+	go func() {
+		defer close(out)
+		defer cancel()
+		for {
+			v, err := b.Recv(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case out <- v:
+			case _, ok := <-wake:
+				// v may be stale; fetch afresh.
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,65 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestToChan(t *testing.T) {
+
+	bc := bchan.NewOf[int](2)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := bc.ToChan(ctx)
+
+	select {
+	case v := <-ch:
+		t.Fatalf("got %d while off", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	bc.Bcast(1)
+	for i := 0; i < 3; i++ {
+		if v := <-ch; v != 1 {
+			t.Fatalf("got %d, want 1", v)
+		}
+	}
+
+	// the other receiver still gets served:
+	// ToChan acks what it takes.
+	if v := <-bc.Ch; v != 1 {
+		t.Fatalf("Ch gave %d, want 1", v)
+	}
+	bc.BcastAck()
+
+	bc.Bcast(2)
+	deadline := time.After(5 * time.Second)
+	for v := <-ch; v != 2; v = <-ch {
+		select {
+		case <-deadline:
+			t.Fatal("never saw 2")
+		default:
+		}
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestToChanClosed(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	ch := bc.ToChan(context.Background())
+	bc.Close()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("expected a closed channel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ToChan channel not closed after Close")
+	}
+}
@@ -0,0 +1,69 @@
+package bchan
+
+import (
+	"context"
+)
+
+// IsOn reports whether broadcasting is on.
+func (b *Of[T]) IsOn() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.on
+}
+
+// Toggle atomically does Off if broadcasting is on,
+// or On if it is off, and returns the new state.
+func (b *Of[T]) Toggle() (on bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("Toggle") {
+		return false
+	}
+	if b.on {
+		b.off()
+	} else {
+		b.turnOn()
+	}
+	return b.on
+}
+
+// WaitUntilOn blocks until broadcasting is on,
+// returning at once if it already is. It returns
+// ctx.Err() if ctx is done first, and ErrClosed
+// if b is closed.
+func (b *Of[T]) WaitUntilOn(ctx context.Context) error {
+	return b.waitOn(ctx, true)
+}
+
+// WaitUntilOff blocks until broadcasting is off,
+// returning at once if it already is, as it is
+// once b has been closed. It returns ctx.Err()
+// if ctx is done first.
+func (b *Of[T]) WaitUntilOff(ctx context.Context) error {
+	return b.waitOn(ctx, false)
+}
+
+func (b *Of[T]) waitOn(ctx context.Context, want bool) error {
+	wake, cancel := b.watch()
+	defer cancel()
+	for {
+		b.mu.Lock()
+		on, closed := b.on, b.closed
+		b.mu.Unlock()
+		switch {
+		case on == want:
+			return nil
+		case closed:
+			return ErrClosed
+		}
+		select {
+		case _, ok := <-wake:
+			if !ok {
+				// recheck: Close turns b off.
+				wake = nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
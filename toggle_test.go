@@ -0,0 +1,64 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestToggle(t *testing.T) {
+
+	bc := bchan.New(1)
+	bc.Set("bill")
+	if bc.IsOn() {
+		t.Fatal("should start off")
+	}
+	if !bc.Toggle() || !bc.IsOn() || len(bc.Ch) != 2 {
+		t.Fatal("Toggle from off should turn on and fill")
+	}
+	if bc.Toggle() || bc.IsOn() || len(bc.Ch) != 0 {
+		t.Fatal("Toggle from on should turn off and drain")
+	}
+	if bc.Get() != "bill" {
+		t.Fatal("Toggle should keep the value")
+	}
+}
+
+func TestWaitUntilOn(t *testing.T) {
+
+	bc := bchan.New(1)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bc.Bcast("up")
+	}()
+	if err := bc.WaitUntilOn(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !bc.IsOn() {
+		t.Fatal("WaitUntilOn returned while off")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bc.WaitUntilOff(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bc.Off()
+	}()
+	if err := bc.WaitUntilOff(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	bc.Close()
+	if err := bc.WaitUntilOn(context.Background()); err != bchan.ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if err := bc.WaitUntilOff(context.Background()); err != nil {
+		t.Fatalf("a closed Bchan is off; got %v", err)
+	}
+}
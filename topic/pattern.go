@@ -0,0 +1,160 @@
+package topic
+
+import (
+	"strings"
+	"sync"
+)
+
+// Msg is a value together with the topic it
+// was published on.
+type Msg[T any] struct {
+	Topic string
+	Val   T
+}
+
+// PatternSub receives from every topic matching
+// a pattern; see SubscribePattern.
+type PatternSub[T any] struct {
+	Ch <-chan Msg[T]
+	ch chan Msg[T]
+
+	pattern []string
+
+	mu      sync.Mutex
+	pending map[string]T
+	order   []string
+	wake    chan struct{}
+	done    chan struct{}
+}
+
+// SubscribePattern subscribes to all topics, present
+// and future, matching pattern. Topic names and
+// patterns are split into levels at '/' or '.'; in
+// a pattern, a level of '+' or '*' matches any one
+// level, and a final '#' matches any number of
+// remaining levels, including none. So "config.*"
+// matches "config.db" but not "config.db.pool", and
+// "sensors/+/temp" matches "sensors/kitchen/temp".
+//
+// Each value Published on a matching topic is
+// delivered on Ch once, tagged with its topic,
+// starting with the current value of each matching
+// topic that has one. If the receiver falls behind,
+// only the latest value per topic is kept. Values
+// broadcast directly on a topic's Bchan, rather than
+// through Publish, are not seen. Release the
+// subscription with UnsubscribePattern.
+func (m *MuxOf[T]) SubscribePattern(pattern string) *PatternSub[T] {
+	ch := make(chan Msg[T])
+	ps := &PatternSub[T]{
+		Ch:      ch,
+		ch:      ch,
+		pattern: levels(pattern),
+		pending: make(map[string]T),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.patterns == nil {
+		m.patterns = make(map[*PatternSub[T]]bool)
+	}
+	m.patterns[ps] = true
+	for name, e := range m.topics {
+		if v, ok := e.b.Cur(); ok && ps.matches(name) {
+			ps.offer(name, v)
+		}
+	}
+	go ps.deliver()
+	return ps
+}
+
+// UnsubscribePattern stops ps and closes ps.Ch.
+func (m *MuxOf[T]) UnsubscribePattern(ps *PatternSub[T]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.patterns[ps] {
+		delete(m.patterns, ps)
+		close(ps.done)
+	}
+}
+
+// notify passes a Publish on to matching
+// pattern subscriptions. caller must hold m.mu.
+func (m *MuxOf[T]) notify(topic string, val T) {
+	for ps := range m.patterns {
+		if ps.matches(topic) {
+			ps.offer(topic, val)
+		}
+	}
+}
+
+func (ps *PatternSub[T]) offer(topic string, val T) {
+	ps.mu.Lock()
+	if _, ok := ps.pending[topic]; !ok {
+		ps.order = append(ps.order, topic)
+	}
+	ps.pending[topic] = val
+	ps.mu.Unlock()
+	select {
+	case ps.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next takes the oldest pending message.
+func (ps *PatternSub[T]) next() (msg Msg[T], ok bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if len(ps.order) == 0 {
+		return msg, false
+	}
+	topic := ps.order[0]
+	ps.order = ps.order[1:]
+	msg = Msg[T]{Topic: topic, Val: ps.pending[topic]}
+	delete(ps.pending, topic)
+	return msg, true
+}
+
+func (ps *PatternSub[T]) deliver() {
+	defer close(ps.ch)
+	for {
+		msg, ok := ps.next()
+		if !ok {
+			select {
+			case <-ps.wake:
+				continue
+			case <-ps.done:
+				return
+			}
+		}
+		select {
+		case ps.ch <- msg:
+		case <-ps.done:
+			return
+		}
+	}
+}
+
+func (ps *PatternSub[T]) matches(topic string) bool {
+	return match(ps.pattern, levels(topic))
+}
+
+func levels(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == '/' || r == '.' })
+}
+
+func match(pattern, topic []string) bool {
+	for i, p := range pattern {
+		switch {
+		case p == "#" && i == len(pattern)-1:
+			return true
+		case i >= len(topic):
+			return false
+		case p == "+" || p == "*" || p == topic[i]:
+		default:
+			return false
+		}
+	}
+	return len(pattern) == len(topic)
+}
@@ -0,0 +1,47 @@
+package topic_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan/topic"
+)
+
+func TestSubscribePattern(t *testing.T) {
+
+	m := topic.NewMuxOf[int](1, 0)
+	defer m.Close()
+
+	m.Publish("sensors/attic/temp", 30)
+	ps := m.SubscribePattern("sensors/+/temp")
+	defer m.UnsubscribePattern(ps)
+
+	m.Publish("sensors/kitchen/temp", 21)
+	m.Publish("sensors/kitchen/humidity", 40)
+	m.Publish("sensors/kitchen/oven/temp", 200)
+
+	got := map[string]int{}
+	for len(got) < 2 {
+		select {
+		case msg := <-ps.Ch:
+			got[msg.Topic] = msg.Val
+		case <-time.After(time.Second):
+			t.Fatalf("timed out, have %v", got)
+		}
+	}
+	if got["sensors/attic/temp"] != 30 || got["sensors/kitchen/temp"] != 21 {
+		t.Fatalf("unexpected messages %v", got)
+	}
+	select {
+	case msg := <-ps.Ch:
+		t.Fatalf("non-matching topic delivered: %v", msg)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cfg := m.SubscribePattern("config.#")
+	defer m.UnsubscribePattern(cfg)
+	m.Publish("config.db.pool", 8)
+	if msg := <-cfg.Ch; msg.Topic != "config.db.pool" {
+		t.Fatalf("expected config.db.pool, got %v", msg)
+	}
+}
@@ -0,0 +1,199 @@
+// package topic multiplexes many named bchan
+// broadcasters behind one Mux, so callers can
+// Publish and Subscribe by topic name without
+// keeping their own map of Bchans.
+package topic
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+// Mux manages named Bchans carrying interface{} values.
+type Mux = MuxOf[interface{}]
+
+// MuxOf manages one bchan.Of[T] per topic.
+// Topics are created on first Publish or
+// Subscribe. A topic with no subscribers
+// that has seen no Publish for the idle
+// period, and has not been handed out by
+// Bchan, is closed and forgotten; this
+// sweep happens as a side effect of using
+// the Mux, so no background goroutine is
+// needed.
+type MuxOf[T any] struct {
+	mu        sync.Mutex
+	diameter  int
+	idle      time.Duration
+	topics    map[string]*entry[T]
+	lastSweep time.Time
+	closed    bool
+
+	patterns map[*PatternSub[T]]bool
+}
+
+type entry[T any] struct {
+	b      *bchan.Of[T]
+	subs   map[*bchan.SubscriptionOf[T]]bool
+	active time.Time
+
+	// pinned is set once the Bchan has been handed
+	// out by Bchan, and keeps it from being swept.
+	pinned bool
+}
+
+// NewMux makes a Mux whose topics are created
+// with the given expectedDiameter (see bchan.New).
+// Idle topics are collected once they have had
+// no subscribers and no Publish for idle;
+// idle <= 0 disables collection.
+func NewMux(expectedDiameter int, idle time.Duration) *Mux {
+	return NewMuxOf[interface{}](expectedDiameter, idle)
+}
+
+// NewMuxOf is the type-parameterized NewMux.
+func NewMuxOf[T any](expectedDiameter int, idle time.Duration) *MuxOf[T] {
+	return &MuxOf[T]{
+		diameter:  expectedDiameter,
+		idle:      idle,
+		topics:    make(map[string]*entry[T]),
+		lastSweep: time.Now(),
+	}
+}
+
+// Publish broadcasts val on topic, creating
+// the topic if need be.
+func (m *MuxOf[T]) Publish(topic string, val T) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.get(topic)
+	e.active = time.Now()
+	e.b.Bcast(val)
+	m.notify(topic, val)
+	m.maybeSweep()
+}
+
+// Subscribe returns a subscription to topic,
+// creating the topic if need be. Release it
+// with Unsubscribe.
+func (m *MuxOf[T]) Subscribe(topic string, opts ...bchan.SubOption) *bchan.SubscriptionOf[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.get(topic)
+	e.active = time.Now()
+	m.maybeSweep()
+	s := e.b.Subscribe(opts...)
+	e.subs[s] = true
+	return s
+}
+
+// Unsubscribe releases a subscription obtained
+// from Subscribe(topic). It does nothing if s is
+// not one of topic's, or was released already.
+func (m *MuxOf[T]) Unsubscribe(topic string, s *bchan.SubscriptionOf[T]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.topics[topic]
+	if !ok || !e.subs[s] {
+		return
+	}
+	delete(e.subs, s)
+	e.b.Unsubscribe(s)
+	e.active = time.Now()
+	m.maybeSweep()
+}
+
+// Bchan returns the Bchan behind topic,
+// creating it if need be. Receivers on its
+// Ch must follow the usual BcastAck rule.
+// The Mux cannot tell when the caller is done
+// with it, so from then on the topic is never
+// swept as idle; it stays until Close.
+func (m *MuxOf[T]) Bchan(topic string) *bchan.Of[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.get(topic)
+	e.active = time.Now()
+	e.pinned = true
+	return e.b
+}
+
+// Topics lists the live topic names, sorted.
+func (m *MuxOf[T]) Topics() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.topics))
+	for name := range m.topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Sweep closes and forgets every idle topic now,
+// rather than waiting for the next automatic sweep.
+func (m *MuxOf[T]) Sweep() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(time.Now())
+}
+
+// Close closes every topic. The Mux must
+// not be used afterwards.
+func (m *MuxOf[T]) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, e := range m.topics {
+		e.b.Close()
+		delete(m.topics, name)
+	}
+	for ps := range m.patterns {
+		delete(m.patterns, ps)
+		close(ps.done)
+	}
+	m.closed = true
+}
+
+// caller must hold m.mu.
+func (m *MuxOf[T]) get(topic string) *entry[T] {
+	if m.closed {
+		panic("topic: Mux used after Close")
+	}
+	e, ok := m.topics[topic]
+	if !ok {
+		e = &entry[T]{b: bchan.NewOf[T](m.diameter), subs: make(map[*bchan.SubscriptionOf[T]]bool)}
+		m.topics[topic] = e
+	}
+	return e
+}
+
+// maybeSweep sweeps at most once every half
+// idle period, to keep Publish cheap.
+// caller must hold m.mu.
+func (m *MuxOf[T]) maybeSweep() {
+	if m.idle <= 0 {
+		return
+	}
+	now := time.Now()
+	if now.Sub(m.lastSweep) < m.idle/2 {
+		return
+	}
+	m.sweep(now)
+}
+
+// caller must hold m.mu.
+func (m *MuxOf[T]) sweep(now time.Time) {
+	m.lastSweep = now
+	if m.idle <= 0 {
+		return
+	}
+	for name, e := range m.topics {
+		if len(e.subs) == 0 && !e.pinned && now.Sub(e.active) >= m.idle {
+			e.b.Close()
+			delete(m.topics, name)
+		}
+	}
+}
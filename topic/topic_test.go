@@ -0,0 +1,86 @@
+package topic_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan/topic"
+)
+
+func TestMux(t *testing.T) {
+
+	m := topic.NewMuxOf[string](1, 20*time.Millisecond)
+	defer m.Close()
+
+	sub := m.Subscribe("weather")
+	m.Publish("weather", "sunny")
+	m.Publish("news", "quiet")
+
+	if v := <-sub.Ch; v != "sunny" {
+		t.Fatalf("expected sunny, got %v", v)
+	}
+	if got := m.Topics(); !reflect.DeepEqual(got, []string{"news", "weather"}) {
+		t.Fatalf("unexpected topics %v", got)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	m.Sweep()
+
+	// news had no subscribers and went idle;
+	// weather is kept alive by its subscriber.
+	if got := m.Topics(); !reflect.DeepEqual(got, []string{"weather"}) {
+		t.Fatalf("expected only weather to survive the sweep, got %v", got)
+	}
+
+	m.Unsubscribe("weather", sub)
+	time.Sleep(30 * time.Millisecond)
+	m.Publish("other", "x") // triggers an automatic sweep
+	if got := m.Topics(); !reflect.DeepEqual(got, []string{"other"}) {
+		t.Fatalf("expected weather collected once unsubscribed, got %v", got)
+	}
+}
+
+func TestMuxBchanPinned(t *testing.T) {
+
+	m := topic.NewMuxOf[string](1, 20*time.Millisecond)
+	defer m.Close()
+
+	b := m.Bchan("held")
+	time.Sleep(30 * time.Millisecond)
+	m.Sweep()
+
+	if got := m.Topics(); !reflect.DeepEqual(got, []string{"held"}) {
+		t.Fatalf("a topic handed out by Bchan was swept; topics %v", got)
+	}
+	if b.IsClosed() {
+		t.Fatal("the topic's Bchan was closed by the sweep")
+	}
+	m.Publish("held", "x")
+	if v := <-b.Ch; v != "x" {
+		t.Fatalf("got %v, want x", v)
+	}
+}
+
+func TestMuxUnsubscribeTwice(t *testing.T) {
+
+	m := topic.NewMuxOf[string](1, 20*time.Millisecond)
+	defer m.Close()
+
+	a := m.Subscribe("weather")
+	b := m.Subscribe("weather")
+	other := m.Subscribe("news")
+	m.Unsubscribe("weather", a)
+	m.Unsubscribe("weather", a)     // again: no effect
+	m.Unsubscribe("weather", other) // not weather's
+	time.Sleep(30 * time.Millisecond)
+	m.Sweep()
+
+	if got := m.Topics(); !reflect.DeepEqual(got, []string{"news", "weather"}) {
+		t.Fatalf("b still holds weather, and other news; topics %v", got)
+	}
+	m.Publish("weather", "sunny")
+	if v, ok := <-b.Ch; !ok || v != "sunny" {
+		t.Fatalf("b got %q, %v; want sunny", v, ok)
+	}
+}
@@ -0,0 +1,106 @@
+// package tracing traces bchan broadcasts with
+// OpenTelemetry.
+//
+// A Tracer wraps an enveloped Bchan (see
+// bchan.NewEnveloped). Its Bcast starts a producer
+// span per broadcast and remembers that span under
+// the sequence number the envelope is stamped with;
+// receivers call Start with the envelope they got to
+// open a consumer span linked back to it, so a value
+// can be followed from producer through every
+// receiver it fanned out to.
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/glycerine/bchan"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope
+// spans are recorded under.
+const ScopeName = "github.com/glycerine/bchan/tracing"
+
+// SeqKey is the span attribute holding
+// the broadcast's sequence number.
+const SeqKey = attribute.Key("bchan.seq")
+
+// Keep is how many recent broadcasts a Tracer can
+// link receiver spans to. A receiver further behind
+// than that gets an unlinked span.
+const Keep = 256
+
+// Tracer traces broadcasts on one Bchan.
+// Every producer should Bcast through the Tracer,
+// so each sequence number is matched to its span.
+type Tracer[T any] struct {
+	B    *bchan.Of[bchan.EnvelopeOf[T]]
+	name string
+	tr   trace.Tracer
+
+	mu    sync.Mutex
+	spans map[uint64]trace.SpanContext
+}
+
+// New returns a Tracer over b, whose spans are
+// named after name. A nil tp means the global
+// TracerProvider.
+func New[T any](name string, b *bchan.Of[bchan.EnvelopeOf[T]], tp trace.TracerProvider) *Tracer[T] {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Tracer[T]{
+		B:     b,
+		name:  name,
+		tr:    tp.Tracer(ScopeName),
+		spans: make(map[uint64]trace.SpanContext),
+	}
+}
+
+// Bcast broadcasts val in a fresh envelope, inside
+// a producer span that is a child of any span in ctx,
+// and returns the sequence number it was given. If
+// val did not go out at once (debounced, coalesced
+// by a rate limit, or dropped), Bcast returns 0, and
+// no receiver span is linked to this one.
+func (t *Tracer[T]) Bcast(ctx context.Context, val T) uint64 {
+	_, span := t.tr.Start(ctx, t.name+" bcast",
+		trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+
+	t.mu.Lock()
+	before := t.B.Seq()
+	bchan.BcastVal(t.B, val)
+	seq := t.B.Seq()
+	if seq == before {
+		t.mu.Unlock()
+		return 0
+	}
+	t.spans[seq] = span.SpanContext()
+	delete(t.spans, seq-Keep)
+	t.mu.Unlock()
+
+	span.SetAttributes(SeqKey.Int64(int64(seq)))
+	return seq
+}
+
+// Start opens a consumer span for handling e, linked
+// to the span of the Bcast that sent it. The caller
+// must End the span when done with e.
+func (t *Tracer[T]) Start(ctx context.Context, e bchan.EnvelopeOf[T]) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(SeqKey.Int64(int64(e.Seq))),
+	}
+	t.mu.Lock()
+	sc, ok := t.spans[e.Seq]
+	t.mu.Unlock()
+	if ok {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+	return t.tr.Start(ctx, t.name+" process", opts...)
+}
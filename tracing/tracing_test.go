@@ -0,0 +1,90 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracerLinksReceivers(t *testing.T) {
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	b := bchan.NewEnveloped[string](2)
+	tr := tracing.New("config", b, tp)
+
+	seq := tr.Bcast(context.Background(), "v1")
+	if seq != 1 {
+		t.Fatalf("expected seq 1, got %d", seq)
+	}
+	for i := 0; i < 2; i++ {
+		e := <-b.Ch
+		b.BcastAck()
+		_, span := tr.Start(context.Background(), e)
+		span.End()
+	}
+
+	ended := sr.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(ended))
+	}
+	prod := ended[0]
+	if prod.Name() != "config bcast" {
+		t.Fatalf("unexpected producer span %q", prod.Name())
+	}
+	for _, s := range ended[1:] {
+		if s.Name() != "config process" {
+			t.Fatalf("unexpected consumer span %q", s.Name())
+		}
+		links := s.Links()
+		if len(links) != 1 || links[0].SpanContext.SpanID() != prod.SpanContext().SpanID() {
+			t.Fatalf("consumer span not linked to the Bcast span: %+v", links)
+		}
+	}
+}
+
+func TestTracerUnknownSeq(t *testing.T) {
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	b := bchan.NewEnveloped[string](1)
+	tr := tracing.New("config", b, tp)
+
+	_, span := tr.Start(context.Background(), bchan.EnvelopeOf[string]{Seq: 42})
+	span.End()
+	if links := sr.Ended()[0].Links(); len(links) != 0 {
+		t.Fatalf("expected no links for an unknown seq, got %+v", links)
+	}
+}
+
+func TestTracerDebounced(t *testing.T) {
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	b := bchan.NewEnveloped[string](1)
+	tr := tracing.New("config", b, tp)
+	tr.Bcast(context.Background(), "v1")
+	b.SetDebounce(time.Hour)
+	if seq := tr.Bcast(context.Background(), "v2"); seq != 0 {
+		t.Fatalf("a debounced Bcast gave seq %d, want 0", seq)
+	}
+
+	// a receiver of v1 links to v1's span only.
+	e := <-b.Ch
+	b.BcastAck()
+	_, span := tr.Start(context.Background(), e)
+	span.End()
+	ended := sr.Ended()
+	links := ended[2].Links()
+	if len(links) != 1 || links[0].SpanContext.SpanID() != ended[0].SpanContext().SpanID() {
+		t.Fatalf("receiver of %q linked to %+v, not the first Bcast", e.Val, links)
+	}
+}
@@ -0,0 +1,49 @@
+package bchan
+
+import (
+	"time"
+)
+
+// BcastTTL broadcasts val for at most ttl. Once
+// ttl passes without val being replaced, it expires
+// as if by Clear: broadcasting stops, Ch is drained
+// and no longer refilled, and Get reports the zero
+// value. This keeps consumers from acting on
+// something like a leader announcement or lock
+// value long after it stopped being valid. Any
+// later Set or Bcast replaces val and its TTL.
+// BcastTTL is immediate, never debounced; over the
+// rate limit, or held back by SetBackpressure, it
+// broadcasts nothing.
+func (b *Of[T]) BcastTTL(val T, ttl time.Duration) {
+	b.lockForBcast()
+	defer b.mu.Unlock()
+	if !b.isOpenFor("BcastTTL") || b.admitNow("BcastTTL", &val, true) != nil {
+		return
+	}
+	b.cancelPending()
+	b.store("BcastTTL", val)
+	b.activate()
+	seq := b.seq
+	b.ttlTimer = b.afterFunc(ttl, func() { b.expire(seq) })
+}
+
+func (b *Of[T]) expire(seq uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.seq != seq || b.closed {
+		return
+	}
+	b.ttlTimer = nil
+	b.logf("bchan: TTL expired seq=%d", b.seq)
+	b.clear()
+}
+
+// stopTTL cancels any pending expiry.
+// Caller must hold b.mu.
+func (b *Of[T]) stopTTL() {
+	if b.ttlTimer != nil {
+		b.ttlTimer.Stop()
+		b.ttlTimer = nil
+	}
+}
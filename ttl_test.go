@@ -0,0 +1,29 @@
+package bchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestBcastTTL(t *testing.T) {
+
+	bc := bchan.NewOf[string](1)
+	bc.BcastTTL("leader=a", 30*time.Millisecond)
+	if v, ok := bc.Cur(); !ok || v != "leader=a" {
+		t.Fatalf("expected leader=a on, got %v %v", v, ok)
+	}
+	eventually(t, "TTL expiry", func() bool {
+		v, ok := bc.Cur()
+		return !ok && v == "" && len(bc.Ch) == 0
+	})
+
+	// a replacement value cancels the TTL.
+	bc.BcastTTL("leader=b", 20*time.Millisecond)
+	bc.Bcast("leader=c")
+	time.Sleep(50 * time.Millisecond)
+	if v, ok := bc.Cur(); !ok || v != "leader=c" {
+		t.Fatalf("replacement should not expire, got %v %v", v, ok)
+	}
+}
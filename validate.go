@@ -0,0 +1,51 @@
+package bchan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalid is wrapped, along with the validator's
+// own error, by the result of a broadcast that was
+// rejected by the validator set with SetValidator,
+// or by a BcastInterceptor.
+var ErrInvalid = errors.New("bchan: invalid value")
+
+// SetValidator has fn vet every value before it can
+// become current. A value fn returns an error for is
+// rejected outright: the current value, Ch, and
+// subscriptions are left untouched, so bad state
+// never fans out. Methods that return an error
+// (TryBcast, BcastAndWait, UnmarshalBinary) return
+// one wrapping both ErrInvalid and fn's; those that
+// report a bool (BcastIfChanged, CompareAndBcast)
+// report false; the rest just log the rejection (see
+// WithLogger). fn runs with b locked and must not
+// call b's methods. SetValidator(nil) removes it.
+func (b *Of[T]) SetValidator(fn func(v T) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.validator = fn
+}
+
+// WithValidator is SetValidator(fn) from the start,
+// so a WithInitial value is vetted too. fn must be a
+// func(T) error for the T of the Bchan being made.
+func WithValidator[T any](fn func(v T) error) Option {
+	return func(c *config) {
+		c.validator = fn
+	}
+}
+
+// validate runs b's validator on val for op.
+// Caller must hold b.mu.
+func (b *Of[T]) validate(op string, val T) error {
+	if b.validator == nil {
+		return nil
+	}
+	if err := b.validator(val); err != nil {
+		b.logf("bchan: %s rejected invalid value: %v", op, err)
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return nil
+}
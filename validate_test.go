@@ -0,0 +1,43 @@
+package bchan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestSetValidator(t *testing.T) {
+
+	errNegative := errors.New("negative")
+	bc := bchan.NewOfWithOptions[int](bchan.WithValidator(func(v int) error {
+		if v < 0 {
+			return errNegative
+		}
+		return nil
+	}))
+	bc.Bcast(1)
+	<-bc.Ch
+	bc.BcastAck()
+
+	bc.Bcast(-1)
+	bc.Set(-2)
+	if v := <-bc.Ch; v != 1 {
+		t.Fatalf("got %d, want the last valid value 1", v)
+	}
+	bc.BcastAck()
+	if seq := bc.Seq(); seq != 1 {
+		t.Fatalf("rejected values must not advance Seq, got %d", seq)
+	}
+
+	err := bc.TryBcast(-3)
+	if !errors.Is(err, bchan.ErrInvalid) || !errors.Is(err, errNegative) {
+		t.Fatalf("TryBcast got %v, want ErrInvalid wrapping the validator's error", err)
+	}
+	if bc.BcastIfChanged(-4) || bc.CompareAndBcast(1, -5) {
+		t.Fatal("an invalid value was reported as broadcast")
+	}
+	if v := bc.Get(); v != 1 {
+		t.Fatalf("current value changed to %d", v)
+	}
+}
@@ -0,0 +1,41 @@
+package bchan
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// errStopped ends a Follow whose consumer
+// has stopped iterating.
+var errStopped = errors.New("bchan: iteration stopped")
+
+// Values returns an iterator over the values
+// broadcast on b: the current one, if broadcasting
+// is on, then each new one, so that
+//
+//	for v := range b.Values(ctx) {
+//		...
+//	}
+//
+// handles every update without the caller receiving
+// from Ch or calling BcastAck. Each value is yielded
+// once; a loop body that is slow to come back skips
+// straight to the latest value, as a linked child
+// would (see AddChild). The loop ends when ctx is
+// done or b is closed.
+func (b *Of[T]) Values(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var seen uint64
+		b.Follow(ctx, func(v T, seq uint64, on bool) error {
+			if !on || seq == seen {
+				return nil
+			}
+			seen = seq
+			if !yield(v) {
+				return errStopped
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package bchan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestValues(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.Bcast(1)
+
+	got := make(chan int)
+	go func() {
+		defer close(got)
+		for v := range bc.Values(context.Background()) {
+			got <- v
+			if v == 3 {
+				return
+			}
+		}
+	}()
+
+	for want := 1; want <= 3; want++ {
+		if v := <-got; v != want {
+			t.Fatalf("got %d, want %d", v, want)
+		}
+		bc.Bcast(want + 1)
+	}
+	if _, ok := <-got; ok {
+		t.Fatal("iteration should have stopped at 3")
+	}
+
+	// ends with the Bchan, or the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range bc.Values(ctx) {
+		t.Fatal("no values after cancel")
+	}
+	bc.Close()
+	for range bc.Values(context.Background()) {
+		t.Fatal("no values after Close")
+	}
+}
+
+func TestValuesHandedOut(t *testing.T) {
+
+	bc := bchan.NewOf[[]int](1)
+	bc.SetClone(bchan.DeepCopy[[]int])
+	bc.Bcast([]int{1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for v := range bc.Values(ctx) {
+		v[0] = 99 // must not reach b's own copy.
+		break
+	}
+	if v := bc.Get(); v[0] != 1 {
+		t.Fatalf("Values yielded b's value itself; now %v", v)
+	}
+}
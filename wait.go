@@ -0,0 +1,178 @@
+package bchan
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAckTimeout is returned by BcastAndWait
+// when too few acks arrive in time.
+var ErrAckTimeout = errors.New("bchan: timed out waiting for acks")
+
+// ErrSuperseded is returned by BcastAndWait and
+// BcastSync when another Set or Bcast replaced the
+// value before enough receivers had it.
+var ErrSuperseded = errors.New("bchan: value superseded before enough acks")
+
+// BcastAndWait broadcasts val, as Bcast would but
+// never debounced, and then blocks until at least n
+// distinct receivers have acked it, for producers
+// that must know the fan-out landed before moving
+// on. Only registered receivers (see RegisterReceiver)
+// acking with their Ack can be told apart, so only
+// they count: a plain BcastAck, or a second Ack by the
+// same receiver, does not. It returns ErrAckTimeout if
+// timeout passes first, or ErrSuperseded if the value
+// is replaced in the meantime. Where Bcast would wait
+// for backpressure, so does BcastAndWait, but where
+// Bcast would drop the value or hold it for the rate
+// limit, BcastAndWait broadcasts nothing and returns
+// ErrBackpressure or ErrRateLimited, as TryBcast does.
+func (b *Of[T]) BcastAndWait(val T, n int, timeout time.Duration) error {
+	b.lockForBcast()
+	if !b.isOpenFor("BcastAndWait") {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	if err := b.bcastNow("BcastAndWait", val); err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	seq := b.seq
+	b.mu.Unlock()
+
+	due, timer := b.timeout(timeout)
+	defer timer.Stop()
+	for {
+		b.mu.Lock()
+		if b.seq != seq {
+			b.mu.Unlock()
+			return ErrSuperseded
+		}
+		// arm the wakeup before counting acks; see ack.
+		if b.ackWait == nil {
+			b.ackWait = make(chan struct{})
+			b.ackWaiting.Store(true)
+		}
+		wake := b.ackWait
+		if len(b.seqAckers) >= n {
+			b.mu.Unlock()
+			return nil
+		}
+		if b.closed {
+			b.mu.Unlock()
+			return ErrClosed
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-due:
+			return ErrAckTimeout
+		}
+	}
+}
+
+// BcastSync broadcasts val, as Bcast would but never
+// debounced, and then blocks until at least n
+// receives of it have been taken off Ch: a barrier
+// for "everyone has the new epoch before I go on".
+// Unlike BcastAndWait it counts the copies actually
+// received rather than acks, though it notices them
+// when receivers ack, so it too relies on the ack
+// rule. It returns ErrSuperseded if the value is
+// replaced first, ErrClosed if b is closed, or
+// ctx.Err() if ctx is done. Backpressure and the rate
+// limit apply as they do to BcastAndWait.
+func (b *Of[T]) BcastSync(val T, n int, ctx context.Context) error {
+	b.lockForBcast()
+	if !b.isOpenFor("BcastSync") {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	if err := b.bcastNow("BcastSync", val); err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	seq := b.seq
+	b.mu.Unlock()
+
+	for {
+		b.mu.Lock()
+		if b.seq != seq {
+			b.mu.Unlock()
+			return ErrSuperseded
+		}
+		// arm the wakeup before counting; see ack.
+		if b.ackWait == nil {
+			b.ackWait = make(chan struct{})
+			b.ackWaiting.Store(true)
+		}
+		wake := b.ackWait
+		if b.taken() >= uint64(n) {
+			b.mu.Unlock()
+			return nil
+		}
+		if b.closed {
+			b.mu.Unlock()
+			return ErrClosed
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// bcastNow is bcast for BcastAndWait and BcastSync:
+// never debounced, and refusing outright a value
+// that bcast would drop or delay.
+// Caller must hold b.mu, taken with lockForBcast.
+func (b *Of[T]) bcastNow(op string, val T) error {
+	if err := b.admitNow(op, &val, true); err != nil {
+		return err
+	}
+	b.cancelPending()
+	b.store(op, val)
+	b.activate()
+	return nil
+}
+
+// admitNow makes bcastNow's checks of val, for the
+// immediate broadcasts that store it themselves:
+// ErrBackpressure, then admitting (reduced, if
+// reduce), then ErrRateLimited, which spends a
+// token if it passes.
+// Caller must hold b.mu, taken with lockForBcast.
+func (b *Of[T]) admitNow(op string, val *T, reduce bool) error {
+	if b.backpressured() {
+		b.ndropped.Add(1)
+		return ErrBackpressure
+	}
+	if err := b.admitting(op, val, reduce); err != nil {
+		return err
+	}
+	if b.limit != nil && !b.limit.take(b.now()) {
+		b.ndropped.Add(1)
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// taken is how many copies of the current value
+// have been received from Ch, or from a Ch retired
+// by Resize or Shrink. Drained copies do not count.
+// Caller must hold b.mu.
+func (b *Of[T]) taken() uint64 {
+	n := b.seqFills - uint64(len(b.Ch))
+	for _, r := range b.retired {
+		if r.filled > len(r.ch) {
+			n += uint64(r.filled - len(r.ch))
+		}
+	}
+	return n
+}
@@ -0,0 +1,127 @@
+package bchan_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestBcastAndWait(t *testing.T) {
+
+	bc := bchan.New(3)
+	for i := 0; i < 3; i++ {
+		r := bc.RegisterReceiver(fmt.Sprintf("r%d", i))
+		go func() {
+			r.Recv(context.Background())
+			r.Ack()
+		}()
+	}
+	if err := bc.BcastAndWait("epoch1", 3, 5*time.Second); err != nil {
+		t.Fatalf("expected 3 receivers' acks, got %v", err)
+	}
+
+	// nobody left to ack
+	if err := bc.BcastAndWait("epoch2", 1, 20*time.Millisecond); err != bchan.ErrAckTimeout {
+		t.Fatalf("expected ErrAckTimeout, got %v", err)
+	}
+}
+
+func TestBcastAndWaitDistinct(t *testing.T) {
+
+	// acks made while Ch is full take the lock-free
+	// path, and anonymous or repeated acks cannot
+	// stand for more than one receiver.
+	bc := bchan.New(3)
+	r := bc.RegisterReceiver("one")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(10 * time.Millisecond)
+		for i := 0; i < 50; i++ {
+			time.Sleep(time.Millisecond)
+			bc.BcastAck()
+			r.Ack()
+		}
+	}()
+	if err := bc.BcastAndWait("full", 2, 200*time.Millisecond); err != bchan.ErrAckTimeout {
+		t.Fatalf("one receiver acking over and over; expected ErrAckTimeout, got %v", err)
+	}
+	<-done
+	if got := bc.Stats().Acks; got != 100 {
+		t.Fatalf("Stats counted %d acks, want 100", got)
+	}
+}
+
+func TestBcastSync(t *testing.T) {
+
+	bc := bchan.NewOf[string](3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			<-bc.Ch
+			bc.BcastAck()
+		}()
+	}
+	if err := bc.BcastSync("epoch1", 3, context.Background()); err != nil {
+		t.Fatalf("expected 3 receives, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bc.BcastSync("epoch2", 1, ctx); err != context.DeadlineExceeded {
+		t.Fatalf("nobody receiving; expected DeadlineExceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bc.Bcast("epoch4")
+	}()
+	if err := bc.BcastSync("epoch3", 1, context.Background()); err != bchan.ErrSuperseded {
+		t.Fatalf("expected ErrSuperseded, got %v", err)
+	}
+}
+
+func TestBcastAndWaitLimits(t *testing.T) {
+
+	bc := bchan.NewOf[int](1)
+	bc.SetRateLimit(1, 1, false)
+	if err := bc.BcastAndWait(1, 0, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := bc.BcastAndWait(2, 0, time.Second); err != bchan.ErrRateLimited {
+		t.Fatalf("over the rate limit; expected ErrRateLimited, got %v", err)
+	}
+	bc.SetRateLimit(0, 0, false)
+
+	bc.SetBackpressure(1, 0)
+	if err := bc.BcastSync(3, 0, context.Background()); err != bchan.ErrBackpressure {
+		t.Fatalf("1 unacked; expected ErrBackpressure, got %v", err)
+	}
+	if v := bc.Get(); v != 1 {
+		t.Fatalf("a refused value was broadcast; have %d", v)
+	}
+}
+
+func TestBcastSyncDrained(t *testing.T) {
+
+	for _, how := range []string{"Off", "Resize"} {
+		bc := bchan.NewOf[string](3)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			if how == "Off" {
+				bc.Off()
+			} else {
+				bc.Resize(5)
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		err := bc.BcastSync("x", 3, ctx)
+		cancel()
+		if err != context.DeadlineExceeded {
+			t.Fatalf("%s took the copies, but nobody received; expected DeadlineExceeded, got %v", how, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package bchan
+
+import (
+	"context"
+	"reflect"
+)
+
+// WaitAny blocks until one of bchans has a value to
+// receive, takes it and does the BcastAck for it, and
+// returns which Bchan it came from, by index, and the
+// value. If ctx is done first it returns index -1 and
+// ctx.Err(); if the Bchan that fires has been closed,
+// its index and ErrClosed. When several are ready at
+// once, one is chosen at random, as with select.
+func WaitAny(ctx context.Context, bchans ...*Bchan) (index int, val interface{}, err error) {
+	return waitAny(ctx, bchans)
+}
+
+// WaitAnyOf is WaitAny for Bchans of one type T.
+func WaitAnyOf[T any](ctx context.Context, bchans ...*Of[T]) (index int, val T, err error) {
+	return waitAny(ctx, bchans)
+}
+
+// waitAny is WaitAnyOf, one frame below the
+// caller's, for the receive recorded for CheckAcks.
+func waitAny[T any](ctx context.Context, bchans []*Of[T]) (index int, val T, err error) {
+	cases := make([]reflect.SelectCase, len(bchans)+1)
+	for i, b := range bchans {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(b.ch())}
+	}
+	cases[len(bchans)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	i, v, ok := reflect.Select(cases)
+	if i == len(bchans) {
+		return -1, val, ctx.Err()
+	}
+	if !ok {
+		return i, val, ErrClosed
+	}
+	bchans[i].received(recvDepth+1, "")
+	bchans[i].BcastAck()
+	val, _ = v.Interface().(T) // a nil interface{} fails the assertion
+	return i, val, nil
+}
@@ -0,0 +1,37 @@
+package bchan_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+)
+
+func TestWaitAny(t *testing.T) {
+
+	a, b := bchan.New(1), bchan.New(1)
+	b.Bcast("from b")
+	i, v, err := bchan.WaitAny(context.Background(), a, b)
+	if err != nil || i != 1 || v != "from b" {
+		t.Fatalf("got %d, %v, %v; want 1, from b", i, v, err)
+	}
+
+	a.Bcast(nil)
+	if i, v, err = bchan.WaitAny(context.Background(), a); i != 0 || v != nil || err != nil {
+		t.Fatalf("got %d, %v, %v; want a nil value from 0", i, v, err)
+	}
+	a.Clear()
+	b.Clear()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if i, _, err = bchan.WaitAny(ctx, a, b); i != -1 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %d, %v; want -1 and the deadline", i, err)
+	}
+
+	a.Close()
+	if i, _, err = bchan.WaitAny(context.Background(), a, b); i != 0 || err != bchan.ErrClosed {
+		t.Fatalf("got %d, %v; want 0, ErrClosed", i, err)
+	}
+}
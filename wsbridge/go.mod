@@ -0,0 +1,10 @@
+module github.com/glycerine/bchan/wsbridge
+
+go 1.23
+
+require (
+	github.com/glycerine/bchan v0.0.0-00010101000000-000000000000
+	golang.org/x/net v0.35.0
+)
+
+replace github.com/glycerine/bchan => ..
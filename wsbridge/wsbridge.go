@@ -0,0 +1,72 @@
+// package wsbridge exposes a Bchan over WebSocket, for
+// live dashboards and other browser-side consumers.
+//
+// Each client that connects is sent the current value
+// straight away, if broadcasting is on, and then every
+// later broadcast, one text message per value. Clients
+// never ack; a slow one skips straight to the latest
+// value rather than holding anyone else up.
+package wsbridge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/glycerine/bchan"
+	"golang.org/x/net/websocket"
+)
+
+// Handler is an http.Handler that upgrades each
+// request to a WebSocket fed from one Bchan.
+type Handler[T any] struct {
+	b *bchan.Of[T]
+
+	// Marshal encodes each value for the wire.
+	// It defaults to json.Marshal; set it before
+	// serving to indent, wrap values in an
+	// envelope, or leave out fields.
+	Marshal func(v T) ([]byte, error)
+}
+
+// NewHandler makes a Handler serving b.
+func NewHandler[T any](b *bchan.Of[T]) *Handler[T] {
+	return &Handler[T]{
+		b: b,
+		Marshal: func(v T) ([]byte, error) {
+			return json.Marshal(v)
+		},
+	}
+}
+
+// ServeHTTP performs the WebSocket handshake and then
+// streams values until the client disconnects or the
+// Bchan is closed.
+func (h *Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket.Handler(h.serve).ServeHTTP(w, r)
+}
+
+func (h *Handler[T]) serve(ws *websocket.Conn) {
+	defer ws.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// anything the client sends is ignored; the
+		// read fails once it has gone away.
+		io.Copy(io.Discard, ws)
+		cancel()
+	}()
+	var sent uint64
+	h.b.Follow(ctx, func(v T, seq uint64, on bool) error {
+		if !on || seq == sent {
+			return nil
+		}
+		data, err := h.Marshal(v)
+		if err != nil {
+			return err
+		}
+		sent = seq
+		return websocket.Message.Send(ws, string(data))
+	})
+}
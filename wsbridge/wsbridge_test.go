@@ -0,0 +1,47 @@
+package wsbridge_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glycerine/bchan"
+	"github.com/glycerine/bchan/wsbridge"
+	"golang.org/x/net/websocket"
+)
+
+func TestHandler(t *testing.T) {
+
+	type reading struct {
+		Temp int `json:"temp"`
+	}
+	src := bchan.NewOf[reading](1)
+	src.Bcast(reading{Temp: 20})
+
+	srv := httptest.NewServer(wsbridge.NewHandler(src))
+	defer srv.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	recv := func() string {
+		t.Helper()
+		var msg string
+		if err := websocket.Message.Receive(ws, &msg); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+	if got := recv(); got != `{"temp":20}` {
+		t.Fatalf("on connect got %s, want the sticky value", got)
+	}
+	src.Bcast(reading{Temp: 21})
+	if got := recv(); got != `{"temp":21}` {
+		t.Fatalf("got %s, want the new broadcast", got)
+	}
+}